@@ -0,0 +1,30 @@
+package main
+
+import "net/http"
+
+// Middleware wraps a handler with extra behaviour
+type Middleware func(http.Handler) http.Handler
+
+// Chain is an ordered list of middleware. The first middleware is the
+// outermost, so it sees the request first and the response last.
+type Chain []Middleware
+
+// NewChain returns a chain of the given middleware in order
+func NewChain(m ...Middleware) Chain {
+	return append(Chain(nil), m...)
+}
+
+// Append returns a new chain with m added after the existing middleware,
+// leaving c unchanged
+func (c Chain) Append(m ...Middleware) Chain {
+	out := make(Chain, 0, len(c)+len(m))
+	return append(append(out, c...), m...)
+}
+
+// Then wraps h with the chain and returns the resulting handler
+func (c Chain) Then(h http.Handler) http.Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		h = c[i](h)
+	}
+	return h
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	marker := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name+" before")
+				next.ServeHTTP(w, r)
+				calls = append(calls, name+" after")
+			})
+		}
+	}
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") })
+
+	base := NewChain(marker("a"), marker("b"))
+	extended := base.Append(marker("c"))
+	extended.Then(final).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"a before", "b before", "c before", "handler", "c after", "b after", "a after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v but got %v", want, calls)
+	}
+
+	calls = nil
+	base.Then(final).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	want = []string{"a before", "b before", "handler", "b after", "a after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected Append to leave the original chain unchanged, got %v", calls)
+	}
+}
@@ -1,21 +1,112 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"log/slog"
 	"net/http"
+	"net/http/pprof"
+	"os"
+	"time"
 
+	"github.com/kabaf81/BuildAWebApplication/pkg/config"
 	"github.com/kabaf81/BuildAWebApplication/pkg/handlers"
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
 )
 
-const portNumber = ":9991"
+var app config.AppConfig
 
 func main() {
-	http.HandleFunc("/", handlers.Home)
-	http.HandleFunc("/About", handlers.About)
-	http.HandleFunc("/SiteMap", handlers.SiteMap)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("invalid configuration: ", err)
+	}
+	app = cfg
 
-	fmt.Println(fmt.Sprintf("Starting Application on port %s", portNumber))
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: app.LogLevel}))
+	defaultTimeout = app.Timeout
+	render.TemplatePath = app.TemplateDir
+	render.ErrorTemplate = app.ErrorTemplate
+	model.SetUniqueNames(app.UniqueNames)
 
-	_ = http.ListenAndServe(portNumber, nil)
+	render.NewTemplates(&app)
 
+	tc, err := render.CreateTemplateCache()
+	if err != nil {
+		log.Fatal("cannot create template cache:", err)
+	}
+
+	if _, ok := tc[app.ErrorTemplate]; !ok {
+		log.Fatalf("error template %s not found in %s", app.ErrorTemplate, app.TemplateDir)
+	}
+
+	app.TemplateCache = tc
+	app.UseCache = !app.Dev
+
+	fmt.Println(fmt.Sprintf("Starting Application on port %s", app.Addr()))
+
+	var store model.UserStore = model.MemoryStore{}
+	if app.CoalesceWindow > 0 {
+		store = model.NewCoalescingStore(store, app.CoalesceWindow)
+	}
+
+	middleware := NewChain(
+		InFlight,
+		Metrics,
+		RequestID,
+		Recoverer(slog.New(slog.NewJSONHandler(os.Stderr, nil))),
+		RequestLogger(logger, "/healthz", "/readyz", "/metrics"),
+		StripSlashes,
+		MaxBytes(app.MaxBody),
+		DelayMiddleware(app.Delay),
+		handlers.WithStore(store),
+	)
+
+	_ = newServer(app, withPprof(app.Pprof, middleware.Then(routes()))).ListenAndServe()
+
+}
+
+// newServer returns a server for h listening on the configured port, with the
+// configured timeouts so slow clients can't hold connections open forever
+func newServer(cfg config.AppConfig, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           h,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// withPprof serves the pprof endpoints under /debug/pprof/ in front of h when
+// enabled. They sit outside the middleware chain so the request timeout and
+// trailing slash redirect don't cut off long CPU profiles or the index page,
+// and they clear the server's write deadline since a profile can take longer
+// than WriteTimeout to collect.
+func withPprof(enabled bool, h http.Handler) http.Handler {
+	if !enabled {
+		return h
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/pprof/", noWriteDeadline(pprof.Index))
+	mux.Handle("/debug/pprof/cmdline", noWriteDeadline(pprof.Cmdline))
+	mux.Handle("/debug/pprof/profile", noWriteDeadline(pprof.Profile))
+	mux.Handle("/debug/pprof/symbol", noWriteDeadline(pprof.Symbol))
+	mux.Handle("/debug/pprof/trace", noWriteDeadline(pprof.Trace))
+	mux.Handle("/", h)
+	return mux
+}
+
+// noWriteDeadline lifts the server's WriteTimeout for requests to h. The
+// server is also hidden from h, since pprof otherwise rejects a ?seconds=
+// longer than WriteTimeout (Go 1.21) or resets the deadline from it.
+func noWriteDeadline(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		h(w, r.WithContext(context.WithValue(r.Context(), http.ServerContextKey, nil)))
+	})
 }
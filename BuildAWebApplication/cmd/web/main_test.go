@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/config"
+)
+
+func TestNewServerTimeouts(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Port = 8080
+	cfg.ReadHeaderTimeout = 2 * time.Second
+	cfg.ReadTimeout = 3 * time.Second
+	cfg.WriteTimeout = 4 * time.Second
+	cfg.IdleTimeout = 5 * time.Second
+
+	h := http.NotFoundHandler()
+	srv := newServer(cfg, h)
+
+	if srv.Addr != ":8080" {
+		t.Errorf("Expected address :8080 but got %s", srv.Addr)
+	}
+	if srv.Handler == nil {
+		t.Error("Expected the handler to be set")
+	}
+	if srv.ReadHeaderTimeout != 2*time.Second || srv.ReadTimeout != 3*time.Second ||
+		srv.WriteTimeout != 4*time.Second || srv.IdleTimeout != 5*time.Second {
+		t.Errorf("Expected timeouts 2s, 3s, 4s and 5s but got %v, %v, %v and %v",
+			srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+
+	if d := config.Defaults(); d.ReadHeaderTimeout == 0 || d.ReadTimeout == 0 || d.WriteTimeout <= d.Timeout || d.IdleTimeout == 0 {
+		t.Errorf("Expected non-zero default timeouts with the write timeout above the handler timeout, got %+v", d)
+	}
+}
+
+func TestWithPprof(t *testing.T) {
+	tests := []struct {
+		enabled  bool
+		path     string
+		expected int
+	}{
+		{true, "/debug/pprof/", http.StatusOK},
+		{true, "/debug/pprof/cmdline", http.StatusOK},
+		{true, "/debug/pprof/heap", http.StatusOK},
+		{true, "/About", http.StatusNotFound},
+		{false, "/debug/pprof/", http.StatusNotFound},
+		{false, "/debug/pprof/heap", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		withPprof(tt.enabled, http.NotFoundHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rr.Code != tt.expected {
+			t.Errorf("pprof=%v %s: expected status %d but got %d", tt.enabled, tt.path, tt.expected, rr.Code)
+		}
+	}
+}
+
+func TestWithPprofOutlivesWriteTimeout(t *testing.T) {
+	srv := httptest.NewUnstartedServer(withPprof(true, http.NotFoundHandler()))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/debug/pprof/profile?seconds=1")
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %s", err)
+	}
+	if res.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Errorf("Expected a 200 with a profile but got %d with %d bytes", res.StatusCode, len(body))
+	}
+}
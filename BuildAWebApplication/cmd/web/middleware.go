@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/handlers"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests served.",
+	}, []string{"path", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"path", "status"})
+)
+
+func init() {
+	registry.MustRegister(httpRequestsTotal, httpRequestDuration)
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Flush passes through to the underlying writer so streamed responses still
+// reach the client as they are written
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// unmatchedRoute is the path label for requests no route matched, so stray
+// URLs can't create new metric series
+const unmatchedRoute = "other"
+
+type routeLabelKey struct{}
+
+// Metrics records the duration and count of every request, labeled by status
+// and by the pattern of the route that served it, as set by labelRoute.
+// Requests no labelled route handled are counted under unmatchedRoute.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		route := unmatchedRoute
+
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), routeLabelKey{}, &route)))
+
+		status := strconv.Itoa(rec.status)
+		httpRequestsTotal.WithLabelValues(route, status).Inc()
+		httpRequestDuration.WithLabelValues(route, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// labelRoute tells Metrics that pattern served the request. The catch-all "/"
+// only claims "/" itself; anything else it gets is a page that doesn't exist.
+func labelRoute(pattern string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := r.Context().Value(routeLabelKey{}).(*string); ok && (pattern != "/" || r.URL.Path == "/") {
+			*route = pattern
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// DelayMiddleware waits d before calling next, to simulate a slow backend.
+// A request whose context is cancelled during the wait returns immediately.
+func DelayMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d > 0 {
+				timer := time.NewTimer(d)
+				defer timer.Stop()
+
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestIDHeader carries the ID used to correlate a request across logs
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// RequestID reuses the incoming X-Request-ID, or generates one, stores it in
+// the request context and echoes it back in the response header
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestIDFromContext returns the request ID stored by RequestID, if any
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Logger logs every request with the default slog logger
+func Logger(next http.Handler) http.Handler {
+	return RequestLogger(slog.Default())(next)
+}
+
+// RequestLogger logs the method, path, status, duration and request ID of every
+// request to logger: at info for 2xx and 3xx, warn for 4xx and error for 5xx.
+// Requests for an ignored path, such as /healthz, are logged at debug so they
+// only show up when debug logging is enabled.
+func RequestLogger(logger *slog.Logger, ignorePaths ...string) func(http.Handler) http.Handler {
+	ignored := make(map[string]bool, len(ignorePaths))
+	for _, p := range ignorePaths {
+		ignored[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			level := statusLevel(rec.status)
+			if ignored[r.URL.Path] {
+				level = slog.LevelDebug
+			}
+			logger.LogAttrs(r.Context(), level, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, rec.status),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("request_id", requestIDFromContext(r.Context())),
+			)
+		})
+	}
+}
+
+// statusLevel maps a response status to the level it is logged at
+func statusLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// RequireJSON rejects POST, PUT and PATCH requests whose Content-Type is not
+// application/json with a JSON 415. Parameters such as charset are allowed, and
+// the media type matches case-insensitively.
+func RequireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				handlers.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recoverer turns a panic in next into a 500. The panic, its stack, the
+// request method and path, and a fresh incident ID are logged to logger; the
+// incident ID is also sent to the client so it can be quoted when reporting
+// the problem. http.ErrAbortHandler is re-panicked so net/http can abort the
+// response as intended.
+func Recoverer(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				incident := newRequestID()
+				logger.LogAttrs(r.Context(), slog.LevelError, "panic recovered",
+					slog.String("incident", incident),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("request_id", requestIDFromContext(r.Context())),
+					slog.Any("panic", p),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"error":    http.StatusText(http.StatusInternalServerError),
+					"incident": incident,
+				})
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MaxBytes limits request bodies to n bytes. Requests that declare a larger
+// Content-Length are rejected up front with 413; otherwise the body is wrapped
+// so handlers see an error once they read past the limit. Either way the
+// client gets the same JSON error body.
+func MaxBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				handlers.WriteError(w, http.StatusRequestEntityTooLarge, http.StatusText(http.StatusRequestEntityTooLarge))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StripSlashes permanently redirects paths ending in a slash to the same path
+// without it, so /About/ and /About reach the same page. "/" is left alone.
+// Leading slashes are collapsed too, so //evil.com/ redirects to /evil.com
+// rather than to another host.
+func StripSlashes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && strings.HasSuffix(r.URL.Path, "/") {
+			u := *r.URL
+			u.Path = "/" + strings.Trim(r.URL.Path, "/")
+			u.RawPath = ""
+			http.Redirect(w, r, u.RequestURI(), http.StatusMovedPermanently)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// timeoutPage is served with a 503 when a handler runs past its deadline
+const timeoutPage = `<!doctype html>
+<html lang="en">
+<head><title>Service Unavailable</title></head>
+<body>
+    <h1>Sorry, that took too long</h1>
+    <p>The page could not be loaded in time. Please try again in a moment.</p>
+</body>
+</html>`
+
+// Timeout responds with 503 and a friendly page when next runs longer than d.
+// The request context is cancelled at the deadline so next can stop its work.
+// A d of zero or less disables the limit.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.TimeoutHandler(next, d, timeoutPage)
+	}
+}
+
+// inFlight is the number of requests currently being served
+var inFlight atomic.Int64
+
+// InFlight counts the requests being served, for /debug/inflight
+func InFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// inFlightHandler reports the current number of in-flight requests as JSON.
+// The request asking is included in the count.
+func inFlightHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "{\"inflight\":%d}\n", inFlight.Load())
+}
+
+// BasicAuth only lets requests carrying the given credentials through to
+// next. Others get a JSON 401 with a WWW-Authenticate challenge. Both fields
+// are always checked, and their SHA-256 digests compared in constant time, so
+// the response time says nothing about which one was wrong, how much of it
+// matched or how long the expected value is.
+func BasicAuth(username, password string, next http.Handler) http.Handler {
+	wantUser := sha256.Sum256([]byte(username))
+	wantPass := sha256.Sum256([]byte(password))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		gotUser := sha256.Sum256([]byte(user))
+		gotPass := sha256.Sum256([]byte(pass))
+		userMatch := subtle.ConstantTimeCompare(gotUser[:], wantUser[:])
+		passMatch := subtle.ConstantTimeCompare(gotPass[:], wantPass[:])
+		if !ok || userMatch&passMatch != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="users", charset="UTF-8"`)
+			handlers.WriteError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writesOnly sends requests that can change state through guarded and
+// everything else, such as GET and HEAD, straight to next
+func writesOnly(guarded, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+		default:
+			guarded.ServeHTTP(w, r)
+		}
+	})
+}
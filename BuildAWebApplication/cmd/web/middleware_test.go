@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	h := Metrics(routes())
+	before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("/sitemap.xml", "200"))
+
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := fmt.Sprintf(`http_requests_total{path="/sitemap.xml",status="200"} %v`, before+2)
+	if !strings.Contains(rr.Body.String(), want) {
+		t.Errorf("Expected /metrics to contain %q, got %s", want, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "http_request_duration_seconds_bucket") {
+		t.Error("Expected /metrics to expose the request duration histogram")
+	}
+}
+
+func TestMetricsRouteLabels(t *testing.T) {
+	h := Metrics(routes())
+
+	tests := []struct {
+		path  string
+		label string
+	}{
+		{"/users/1", "/users/"},
+		{"/users/2", "/users/"},
+		{"/", "/"},
+		{"/no-such-page", unmatchedRoute},
+		{"/another/missing/page", unmatchedRoute},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(tt.label, strconv.Itoa(http.StatusOK)))
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		counter := httpRequestsTotal.WithLabelValues(tt.label, strconv.Itoa(rr.Code))
+		if rr.Code == http.StatusOK {
+			if got := testutil.ToFloat64(counter); got != before+1 {
+				t.Errorf("%s: expected the %q count to rise to %v but got %v", tt.path, tt.label, before+1, got)
+			}
+		} else if testutil.ToFloat64(counter) < 1 {
+			t.Errorf("%s: expected a count under %q with status %d", tt.path, tt.label, rr.Code)
+		}
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/yet-another-missing-page", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/3", nil))
+	series := testutil.CollectAndCount(httpRequestsTotal)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/and-one-more", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/4", nil))
+	if got := testutil.CollectAndCount(httpRequestsTotal); got != series {
+		t.Errorf("Expected new unmatched paths and user IDs to reuse existing series, got %d new", got-series)
+	}
+}
+
+func TestDelayMiddleware(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+	h := DelayMiddleware(50 * time.Millisecond)(next)
+
+	start := time.Now()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected a delay of at least 50ms but got %v", elapsed)
+	}
+	if !called {
+		t.Error("Expected the next handler to be called")
+	}
+}
+
+func TestDelayMiddlewareCancelled(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+	h := DelayMiddleware(time.Minute)(next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected a cancelled request to return promptly but took %v", elapsed)
+	}
+	if called {
+		t.Error("Expected the next handler not to be called")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestIDHeader, "abc-123")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if seen != "abc-123" {
+		t.Errorf("Expected the incoming request ID in the context, got %q", seen)
+	}
+	if got := rr.Header().Get(requestIDHeader); got != "abc-123" {
+		t.Errorf("Expected the incoming request ID to be echoed, got %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seen == "" || seen == "abc-123" {
+		t.Errorf("Expected a generated request ID, got %q", seen)
+	}
+	if got := rr.Header().Get(requestIDHeader); got != seen {
+		t.Errorf("Expected the generated request ID %q to be echoed, got %q", seen, got)
+	}
+}
+
+func TestLoggerIncludesRequestID(t *testing.T) {
+	var buf strings.Builder
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := RequestID(Logger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	req := httptest.NewRequest(http.MethodGet, "/About", nil)
+	req.Header.Set(requestIDHeader, "abc-123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "GET /About 200") || !strings.Contains(buf.String(), "request_id=abc-123") {
+		t.Errorf("Unexpected log line %q", buf.String())
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	h := MaxBytes(64)(routes())
+	body := `{"firstName":"` + strings.Repeat("a", 200) + `","lastName":"Kaba"}`
+
+	post := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, post(body))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d but got %d", http.StatusRequestEntityTooLarge, rr.Code)
+	}
+	expectJSONError(t, "content-length", rr)
+	declared := rr.Body.String()
+
+	// without a Content-Length the limit is enforced while reading
+	req := post(body)
+	req.ContentLength = -1
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d for a streamed body but got %d", http.StatusRequestEntityTooLarge, rr.Code)
+	}
+	if rr.Body.String() != declared {
+		t.Errorf("Expected the same error body either way, got %q and %q", declared, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, post(`{"firstName":"Fadi","lastName":"Kaba"}`))
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected a small body to be accepted, got %d", rr.Code)
+	}
+}
+
+func TestStripSlashes(t *testing.T) {
+	h := StripSlashes(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path     string
+		status   int
+		location string
+	}{
+		{"/About/", http.StatusMovedPermanently, "/About"},
+		{"/About//?x=1", http.StatusMovedPermanently, "/About?x=1"},
+		{"/About", http.StatusOK, ""},
+		{"/", http.StatusOK, ""},
+		{"//evil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{"///evil.com//", http.StatusMovedPermanently, "/evil.com"},
+		{"//", http.StatusMovedPermanently, "/"},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.path, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s: expected Location %q but got %q", tt.path, tt.location, got)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(time.Second):
+		}
+	})
+	h := Timeout(20 * time.Millisecond)(slow)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d but got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "took too long") {
+		t.Errorf("Expected the timeout page, got %q", rr.Body.String())
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow handler's context to be cancelled")
+	}
+
+	rr = httptest.NewRecorder()
+	Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected a fast handler to succeed, got %d", rr.Code)
+	}
+}
+
+func TestStatusRecorderFlush(t *testing.T) {
+	h := Metrics(Logger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	})))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !rr.Flushed {
+		t.Error("Expected the flush to reach the underlying writer")
+	}
+}
+
+func TestRequestLoggerLevels(t *testing.T) {
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	h := RequestLogger(logger, "/healthz")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+	}))
+
+	tests := []struct {
+		path  string
+		level string
+	}{
+		{"/?status=200", "level=INFO"},
+		{"/?status=302", "level=INFO"},
+		{"/?status=404", "level=WARN"},
+		{"/?status=503", "level=ERROR"},
+		{"/healthz?status=200", ""},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if tt.level == "" {
+			if buf.Len() != 0 {
+				t.Errorf("%s: expected an ignored path not to be logged, got %q", tt.path, buf.String())
+			}
+			continue
+		}
+		if !strings.Contains(buf.String(), tt.level) {
+			t.Errorf("%s: expected %s in %q", tt.path, tt.level, buf.String())
+		}
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	})
+	mux.HandleFunc("/debug/inflight", inFlightHandler)
+	h := InFlight(mux)
+
+	count := func() string {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/inflight", nil))
+		return strings.TrimSpace(rr.Body.String())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		close(done)
+	}()
+	<-entered
+
+	// the slow request plus the one asking
+	if got := count(); got != `{"inflight":2}` {
+		t.Errorf("Expected 2 in-flight requests but got %s", got)
+	}
+
+	close(release)
+	<-done
+	if got := count(); got != `{"inflight":1}` {
+		t.Errorf("Expected 1 in-flight request after the slow one finished but got %s", got)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	h := BasicAuth("admin", "s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name   string
+		user   string
+		pass   string
+		header bool
+		status int
+	}{
+		{"correct", "admin", "s3cret", true, http.StatusOK},
+		{"wrong-password", "admin", "guess", true, http.StatusUnauthorized},
+		{"wrong-user", "root", "s3cret", true, http.StatusUnauthorized},
+		{"password-prefix", "admin", "s3cre", true, http.StatusUnauthorized},
+		{"password-longer", "admin", "s3cret!", true, http.StatusUnauthorized},
+		{"missing-header", "", "", false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		if tt.header {
+			req.SetBasicAuth(tt.user, tt.pass)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+		}
+		challenge := rr.Header().Get("WWW-Authenticate")
+		if tt.status == http.StatusUnauthorized && !strings.HasPrefix(challenge, "Basic ") {
+			t.Errorf("%s: expected a Basic WWW-Authenticate challenge, got %q", tt.name, challenge)
+		}
+		if tt.status == http.StatusUnauthorized {
+			expectJSONError(t, tt.name, rr)
+		}
+	}
+}
+
+// expectJSONError checks rr carries the API's JSON error body
+func expectJSONError(t *testing.T, name string, rr *httptest.ResponseRecorder) {
+	t.Helper()
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: expected a JSON error but got Content-Type %q", name, ct)
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Error == "" {
+		t.Errorf("%s: expected an {\"error\"} body but got %q (%v)", name, rr.Body.String(), err)
+	}
+}
+
+func TestProtectWrites(t *testing.T) {
+	saved := app
+	app.AdminUser, app.AdminPassword = "admin", "s3cret"
+	defer func() { app = saved }()
+
+	h := routes()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		auth   bool
+		status int
+	}{
+		{"list-public", http.MethodGet, "/users", "", false, http.StatusOK},
+		{"get-public", http.MethodGet, "/users/999999", "", false, http.StatusNotFound},
+		{"create-anonymous", http.MethodPost, "/users", `{"firstName":"Fadi","lastName":"Kaba"}`, false, http.StatusUnauthorized},
+		{"create-authed", http.MethodPost, "/users", `{"firstName":"Fadi","lastName":"Kaba"}`, true, http.StatusCreated},
+		{"delete-anonymous", http.MethodDelete, "/users/1", "", false, http.StatusUnauthorized},
+		{"update-anonymous", http.MethodPut, "/users/1", `{"firstName":"Jane","lastName":"Doe"}`, false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.auth {
+			req.SetBasicAuth("admin", "s3cret")
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+		}
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	var buf strings.Builder
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	h := Recoverer(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d but got %d", http.StatusInternalServerError, rr.Code)
+	}
+	var body struct {
+		Incident string `json:"incident"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Incident == "" {
+		t.Fatalf("Expected an incident ID in the response, got %q (%v)", rr.Body.String(), err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(buf.String()), &entry); err != nil {
+		t.Fatalf("Expected a JSON log entry, got %q (%v)", buf.String(), err)
+	}
+	if entry["incident"] != body.Incident {
+		t.Errorf("Expected incident %s in the log but got %v", body.Incident, entry["incident"])
+	}
+	if entry["method"] != http.MethodPost || entry["path"] != "/users" || entry["panic"] != "boom" {
+		t.Errorf("Expected the method, path and panic value in the log, got %v", entry)
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "TestRecoverer") {
+		t.Errorf("Expected the stack in the log, got %q", stack)
+	}
+}
+
+func TestRecovererAbortHandler(t *testing.T) {
+	h := Recoverer(slog.Default())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to be re-panicked, got %v", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRequireJSON(t *testing.T) {
+	h := RequireJSON(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		status      int
+	}{
+		{"json", http.MethodPost, "application/json", http.StatusOK},
+		{"json-charset", http.MethodPut, "application/json; charset=utf-8", http.StatusOK},
+		{"json-uppercase", http.MethodPost, "Application/JSON", http.StatusOK},
+		{"missing", http.MethodPost, "", http.StatusUnsupportedMediaType},
+		{"wrong", http.MethodPost, "text/plain", http.StatusUnsupportedMediaType},
+		{"form", http.MethodPut, "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"get-ignored", http.MethodGet, "", http.StatusOK},
+		{"delete-ignored", http.MethodDelete, "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/users", strings.NewReader("{}"))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"demo/menu"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/handlers"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// defaultTimeout bounds page handlers that do not set their own timeout
+var defaultTimeout = 10 * time.Second
+
+// routeInfo describes a registered route for /debug/routes
+type routeInfo struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods"`
+}
+
+func routes() http.Handler {
+	mux := http.NewServeMux()
+
+	var listed []routeInfo
+	handle := func(path string, h http.Handler, methods ...string) {
+		mux.Handle(path, labelRoute(path, h))
+		listed = append(listed, routeInfo{Path: path, Methods: methods})
+	}
+
+	for _, rt := range handlers.Routes() {
+		d := rt.Timeout
+		if d == 0 {
+			d = defaultTimeout
+		}
+		handle(rt.Path, Timeout(d)(rt.Handler), http.MethodGet)
+	}
+	handle("/sitemap.xml", http.HandlerFunc(handlers.SiteMapXML), http.MethodGet)
+	handle("/api/menu", handlers.MenuJSON(menu.Default()), http.MethodGet)
+	handle("/healthz", http.HandlerFunc(handlers.Healthz), http.MethodGet)
+	handle("/readyz", http.HandlerFunc(handlers.Readyz), http.MethodGet)
+	handle("/users", protectWrites(RequireJSON(http.HandlerFunc(handlers.Users))), http.MethodGet, http.MethodPost)
+	handle("/users/", protectWrites(RequireJSON(http.HandlerFunc(handlers.UserByID))), http.MethodGet, http.MethodPut, http.MethodDelete)
+	handle("/users/schema", http.HandlerFunc(handlers.UsersSchema), http.MethodGet)
+	handle("/users/count", http.HandlerFunc(handlers.UsersCount), http.MethodGet)
+	handle("/debug/inflight", http.HandlerFunc(inFlightHandler), http.MethodGet)
+	handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), http.MethodGet)
+
+	// the route list is only exposed while developing
+	if app.Dev {
+		handle("/debug/routes", routesHandler(&listed), http.MethodGet)
+	}
+
+	return mux
+}
+
+// routesHandler serves the routes in *listed as JSON. It takes a pointer so
+// the list it serves includes /debug/routes itself.
+func routesHandler(listed *[]routeInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(*listed)
+	})
+}
+
+// protectWrites puts the mutating methods of h behind basic auth when admin
+// credentials are configured
+func protectWrites(h http.Handler) http.Handler {
+	if app.AdminUser == "" {
+		return h
+	}
+	return writesOnly(BasicAuth(app.AdminUser, app.AdminPassword, h), h)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	saved := app
+	defer func() { app = saved }()
+
+	app.Dev = true
+	rr := httptest.NewRecorder()
+	routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d in dev mode but got %d", http.StatusOK, rr.Code)
+	}
+	var listed []routeInfo
+	if err := json.Unmarshal(rr.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("Expected a JSON route list but got %v", err)
+	}
+
+	methods := map[string][]string{}
+	for _, rt := range listed {
+		methods[rt.Path] = rt.Methods
+	}
+	want := map[string][]string{
+		"/About":        {http.MethodGet},
+		"/users":        {http.MethodGet, http.MethodPost},
+		"/users/":       {http.MethodGet, http.MethodPut, http.MethodDelete},
+		"/debug/routes": {http.MethodGet},
+	}
+	for path, m := range want {
+		if !reflect.DeepEqual(methods[path], m) {
+			t.Errorf("Expected %s with methods %v but got %v", path, m, methods[path])
+		}
+	}
+
+	app.Dev = false
+	rr = httptest.NewRecorder()
+	routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d outside dev mode but got %d", http.StatusNotFound, rr.Code)
+	}
+}
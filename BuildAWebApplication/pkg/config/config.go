@@ -0,0 +1,49 @@
+package config
+
+import (
+	"log/slog"
+	"text/template"
+	"time"
+)
+
+// AppConfig holds the application config
+type AppConfig struct {
+	UseCache      bool
+	TemplateCache map[string]*template.Template
+	// Functions are added to the render package's template functions,
+	// overriding any with the same name
+	Functions template.FuncMap
+
+	// Settings resolved by LoadConfig
+	Port          int
+	TemplateDir   string
+	ErrorTemplate string
+	Dev           bool
+	Delay         time.Duration
+	MaxBody       int64
+	Timeout       time.Duration
+	LogLevel      slog.Level
+
+	// Pprof serves the net/http/pprof profiling endpoints under /debug/pprof/.
+	// It is off by default since profiles expose the program's internals.
+	Pprof bool
+
+	// UniqueNames rejects a user whose name matches one already stored
+	UniqueNames bool
+
+	// CoalesceWindow folds repeated adds of the same name within the window
+	// into one insert; zero turns coalescing off
+	CoalesceWindow time.Duration
+
+	// Server timeouts, see http.Server
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// AdminUser and AdminPassword guard the /users write endpoints with basic
+	// auth. They are only read from the environment; an empty AdminUser leaves
+	// the endpoints open.
+	AdminUser     string
+	AdminPassword string
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvPort        = "WEB_PORT"
+	EnvTemplateDir = "WEB_TEMPLATE_DIR"
+	EnvDev         = "WEB_DEV"
+	EnvDelay       = "WEB_DELAY"
+	EnvMaxBody     = "WEB_MAX_BODY"
+	EnvTimeout     = "WEB_TIMEOUT"
+	EnvLogLevel    = "WEB_LOG_LEVEL"
+	EnvAdminUser   = "WEB_ADMIN_USER"
+	EnvAdminPass   = "WEB_ADMIN_PASSWORD"
+
+	EnvReadHeaderTimeout = "WEB_READ_HEADER_TIMEOUT"
+	EnvReadTimeout       = "WEB_READ_TIMEOUT"
+	EnvWriteTimeout      = "WEB_WRITE_TIMEOUT"
+	EnvIdleTimeout       = "WEB_IDLE_TIMEOUT"
+	EnvCoalesceWindow    = "WEB_COALESCE_WINDOW"
+	EnvErrorTemplate     = "WEB_ERROR_TEMPLATE"
+)
+
+// Defaults returns the settings used when neither a flag nor an env var is set
+func Defaults() AppConfig {
+	return AppConfig{
+		Port:          9991,
+		TemplateDir:   "./templates",
+		ErrorTemplate: "error.page.tmpl.html",
+		MaxBody:       1 << 20,
+		Timeout:       10 * time.Second,
+
+		// WriteTimeout leaves room for a page to hit Timeout and still send its 503
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+}
+
+// LoadConfig resolves the settings from the command line and environment. A
+// flag wins over its env var, which wins over the default.
+func LoadConfig() (AppConfig, error) {
+	return load(os.Args[1:], os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (AppConfig, error) {
+	cfg := Defaults()
+	if err := cfg.applyEnv(lookupEnv); err != nil {
+		return AppConfig{}, err
+	}
+
+	// Env values become the flag defaults, so only flags actually given override them
+	fs := flag.NewFlagSet("web", flag.ContinueOnError)
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "port to listen on")
+	fs.StringVar(&cfg.TemplateDir, "templates", cfg.TemplateDir, "directory templates are loaded from")
+	fs.StringVar(&cfg.ErrorTemplate, "error-template", cfg.ErrorTemplate, "page template, in the template directory, used for error responses")
+	fs.BoolVar(&cfg.Dev, "dev", cfg.Dev, "re-parse templates on every request instead of using the cache")
+	fs.DurationVar(&cfg.Delay, "delay", cfg.Delay, "artificial latency added to every request, e.g. 500ms")
+	fs.Int64Var(&cfg.MaxBody, "max-body", cfg.MaxBody, "maximum request body size in bytes")
+	fs.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "maximum time a page handler may run before a 503 is returned")
+	fs.DurationVar(&cfg.ReadHeaderTimeout, "read-header-timeout", cfg.ReadHeaderTimeout, "maximum time to read request headers")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "maximum time to read a whole request, including the body")
+	fs.DurationVar(&cfg.WriteTimeout, "write-timeout", cfg.WriteTimeout, "maximum time to write a response")
+	fs.DurationVar(&cfg.IdleTimeout, "idle-timeout", cfg.IdleTimeout, "how long an idle keep-alive connection is kept open")
+	fs.BoolVar(&cfg.Pprof, "pprof", cfg.Pprof, "serve profiling endpoints under /debug/pprof/")
+	fs.BoolVar(&cfg.UniqueNames, "unique-names", cfg.UniqueNames, "reject users whose first and last name match an existing user")
+	fs.DurationVar(&cfg.CoalesceWindow, "coalesce-window", cfg.CoalesceWindow, "treat adds of the same name within this window as one, e.g. 2s; 0 disables")
+	fs.TextVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "minimum level logged: debug, info, warn or error")
+	if err := fs.Parse(args); err != nil {
+		return AppConfig{}, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return AppConfig{}, err
+	}
+	return cfg, nil
+}
+
+func (c *AppConfig) applyEnv(lookupEnv func(string) (string, bool)) error {
+	var err error
+	if v, ok := lookupEnv(EnvPort); ok {
+		if c.Port, err = strconv.Atoi(v); err != nil {
+			return fmt.Errorf("%s: %w", EnvPort, err)
+		}
+	}
+	if v, ok := lookupEnv(EnvTemplateDir); ok {
+		c.TemplateDir = v
+	}
+	if v, ok := lookupEnv(EnvErrorTemplate); ok {
+		c.ErrorTemplate = v
+	}
+	if v, ok := lookupEnv(EnvDev); ok {
+		if c.Dev, err = strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("%s: %w", EnvDev, err)
+		}
+	}
+	if v, ok := lookupEnv(EnvDelay); ok {
+		if c.Delay, err = time.ParseDuration(v); err != nil {
+			return fmt.Errorf("%s: %w", EnvDelay, err)
+		}
+	}
+	if v, ok := lookupEnv(EnvMaxBody); ok {
+		if c.MaxBody, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("%s: %w", EnvMaxBody, err)
+		}
+	}
+	if v, ok := lookupEnv(EnvTimeout); ok {
+		if c.Timeout, err = time.ParseDuration(v); err != nil {
+			return fmt.Errorf("%s: %w", EnvTimeout, err)
+		}
+	}
+	if v, ok := lookupEnv(EnvLogLevel); ok {
+		if err := c.LogLevel.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("%s: %w", EnvLogLevel, err)
+		}
+	}
+	for env, d := range map[string]*time.Duration{
+		EnvReadHeaderTimeout: &c.ReadHeaderTimeout,
+		EnvReadTimeout:       &c.ReadTimeout,
+		EnvWriteTimeout:      &c.WriteTimeout,
+		EnvIdleTimeout:       &c.IdleTimeout,
+		EnvCoalesceWindow:    &c.CoalesceWindow,
+	} {
+		if v, ok := lookupEnv(env); ok {
+			if *d, err = time.ParseDuration(v); err != nil {
+				return fmt.Errorf("%s: %w", env, err)
+			}
+		}
+	}
+	c.AdminUser, _ = lookupEnv(EnvAdminUser)
+	c.AdminPassword, _ = lookupEnv(EnvAdminPass)
+	return nil
+}
+
+func (c AppConfig) validate() error {
+	switch {
+	case c.Port < 1 || c.Port > 65535:
+		return fmt.Errorf("port %d is out of range", c.Port)
+	case c.TemplateDir == "":
+		return errors.New("template directory is required")
+	case c.ErrorTemplate == "":
+		return errors.New("error template is required")
+	case c.Delay < 0:
+		return errors.New("delay must not be negative")
+	case c.MaxBody <= 0:
+		return errors.New("max body must be positive")
+	case c.Timeout < 0:
+		return errors.New("timeout must not be negative")
+	case c.ReadHeaderTimeout < 0 || c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0:
+		return errors.New("server timeouts must not be negative")
+	case c.CoalesceWindow < 0:
+		return errors.New("coalesce window must not be negative")
+	case c.AdminUser != "" && c.AdminPassword == "":
+		return fmt.Errorf("%s is required when %s is set", EnvAdminPass, EnvAdminUser)
+	}
+
+	info, err := os.Stat(c.TemplateDir)
+	if err != nil {
+		return fmt.Errorf("template directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("template directory %s is not a directory", c.TemplateDir)
+	}
+	return nil
+}
+
+// Addr returns the listen address for the configured port
+func (c AppConfig) Addr() string {
+	return ":" + strconv.Itoa(c.Port)
+}
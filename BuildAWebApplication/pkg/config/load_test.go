@@ -0,0 +1,93 @@
+package config
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		port    int
+		delay   time.Duration
+		timeout time.Duration
+	}{
+		{"defaults", nil, nil, 9991, 0, 10 * time.Second},
+		{"env", nil, map[string]string{EnvPort: "8080", EnvDelay: "1s"}, 8080, time.Second, 10 * time.Second},
+		{"flag-over-env", []string{"-port", "9000"}, map[string]string{EnvPort: "8080", EnvDelay: "1s"}, 9000, time.Second, 10 * time.Second},
+		{"flag-over-default", []string{"-timeout", "2s"}, nil, 9991, 0, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		args := append([]string{"-templates", dir}, tt.args...)
+		cfg, err := load(args, env(tt.env))
+		if err != nil {
+			t.Errorf("%s: got an error when should not have: %v", tt.name, err)
+			continue
+		}
+		if cfg.Port != tt.port || cfg.Delay != tt.delay || cfg.Timeout != tt.timeout {
+			t.Errorf("%s: expected port %d, delay %v and timeout %v but got %d, %v and %v",
+				tt.name, tt.port, tt.delay, tt.timeout, cfg.Port, cfg.Delay, cfg.Timeout)
+		}
+	}
+
+	cfg, err := load(nil, env(map[string]string{EnvTemplateDir: dir, EnvDev: "true"}))
+	if err != nil || cfg.TemplateDir != dir || !cfg.Dev {
+		t.Errorf("Expected the env template dir and dev mode, got %+v (%v)", cfg, err)
+	}
+	cfg, err = load([]string{"-templates", dir, "-log-level", "warn"}, env(map[string]string{EnvLogLevel: "debug"}))
+	if err != nil || cfg.LogLevel != slog.LevelWarn {
+		t.Errorf("Expected the -log-level flag to win, got %v (%v)", cfg.LogLevel, err)
+	}
+	cfg, err = load([]string{"-templates", dir, "-read-timeout", "3s"}, env(map[string]string{EnvReadTimeout: "1s", EnvIdleTimeout: "2m"}))
+	if err != nil || cfg.ReadTimeout != 3*time.Second || cfg.IdleTimeout != 2*time.Minute || cfg.WriteTimeout != Defaults().WriteTimeout {
+		t.Errorf("Expected read timeout 3s, idle timeout 2m and the default write timeout, got %v, %v and %v (%v)",
+			cfg.ReadTimeout, cfg.IdleTimeout, cfg.WriteTimeout, err)
+	}
+	if cfg.Addr() != ":9991" {
+		t.Errorf("Expected address :9991 but got %s", cfg.Addr())
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{"port-range", []string{"-port", "70000"}, nil},
+		{"port-env", nil, map[string]string{EnvPort: "abc"}},
+		{"dev-env", nil, map[string]string{EnvDev: "maybe"}},
+		{"negative-delay", []string{"-delay", "-1s"}, nil},
+		{"zero-max-body", []string{"-max-body", "0"}, nil},
+		{"negative-timeout", nil, map[string]string{EnvTimeout: "-5s"}},
+		{"missing-templates", []string{"-templates", dir + "/missing"}, nil},
+		{"unknown-flag", []string{"-nope"}, nil},
+		{"log-level-env", nil, map[string]string{EnvLogLevel: "loud"}},
+		{"admin-without-password", nil, map[string]string{EnvAdminUser: "admin"}},
+		{"empty-error-template", []string{"-error-template", ""}, nil},
+		{"write-timeout-env", nil, map[string]string{EnvWriteTimeout: "soon"}},
+		{"negative-idle-timeout", []string{"-idle-timeout", "-1s"}, nil},
+	}
+
+	for _, tt := range tests {
+		args := append([]string{"-templates", dir}, tt.args...)
+		if _, err := load(args, env(tt.env)); err == nil {
+			t.Errorf("%s: expected an error but didn't get one", tt.name)
+		}
+	}
+}
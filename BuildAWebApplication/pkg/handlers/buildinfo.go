@@ -0,0 +1,32 @@
+package handlers
+
+import "net/http"
+
+// Build details, set at link time with e.g.
+//
+//	go build -ldflags "-X github.com/kabaf81/BuildAWebApplication/pkg/handlers.Version=1.2.0"
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildDate = "dev"
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+}
+
+// CurrentBuildInfo returns the build details set at link time
+func CurrentBuildInfo() BuildInfo {
+	return BuildInfo{Version: Version, Commit: Commit, BuildDate: BuildDate}
+}
+
+// Healthz reports that the server is up, along with its build details
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+		BuildInfo
+	}{Status: "ok", BuildInfo: CurrentBuildInfo()})
+}
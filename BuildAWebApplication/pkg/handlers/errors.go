@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"`
+}
+
+// WriteError writes msg with status as the JSON error body the API uses,
+// {"error": msg}, for middleware that rejects requests before a handler runs
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// writeModelError writes err as a JSON error body with the status code that
+// matches the model error it wraps
+func writeModelError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	resp := errorResponse{Error: err.Error()}
+
+	var verr *model.ErrValidation
+	switch {
+	case errors.Is(err, model.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &verr):
+		status = http.StatusBadRequest
+		resp.Field = verr.Field
+	case errors.Is(err, model.ErrDuplicate):
+		status = http.StatusConflict
+	case errors.Is(err, model.ErrUnsupported):
+		status = http.StatusNotImplemented
+	default:
+		resp.Error = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// wantsJSON reports whether the client asked for JSON ahead of HTML
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	jsonAt := strings.Index(accept, "application/json")
+	htmlAt := strings.Index(accept, "text/html")
+	return jsonAt >= 0 && (htmlAt < 0 || jsonAt < htmlAt)
+}
+
+// respondError writes msg with the given status, as JSON for clients that
+// accept it and as the HTML error page for everyone else
+func respondError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	if wantsJSON(r) {
+		writeJSON(w, status, errorResponse{Error: msg})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	render.RenderTemplate(w, render.ErrorTemplate, render.ErrorData(status, msg))
+}
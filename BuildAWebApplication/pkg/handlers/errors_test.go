@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+func TestWriteModelError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{"not-found", fmt.Errorf("get user: %w", model.ErrUserNotFound), http.StatusNotFound, ""},
+		{"validation", &model.ErrValidation{Field: "lastName", Reason: "is required"}, http.StatusBadRequest, "lastName"},
+		{"duplicate", model.ErrDuplicate, http.StatusConflict, ""},
+		{"other", errors.New("disk on fire"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		writeModelError(rr, tt.err)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: expected application/json but got %q", tt.name, ct)
+		}
+
+		var body errorResponse
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Errorf("%s: invalid JSON body: %v", tt.name, err)
+			continue
+		}
+		if body.Field != tt.field {
+			t.Errorf("%s: expected field %q but got %q", tt.name, tt.field, body.Field)
+		}
+		if body.Error == "" {
+			t.Errorf("%s: expected an error message", tt.name)
+		}
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"html", "text/html,application/xhtml+xml", "text/html; charset=utf-8", "<p>page not found</p>"},
+		{"json", "application/json", "application/json", `{"error":"page not found"}`},
+		{"json-preferred", "application/json, text/html;q=0.5", "application/json", `{"error":"page not found"}`},
+		{"default", "", "text/html; charset=utf-8", "<p>page not found</p>"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		req.Header.Set("Accept", tt.accept)
+		rr := httptest.NewRecorder()
+		respondError(rr, req, http.StatusNotFound, "page not found")
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status %d but got %d", tt.name, http.StatusNotFound, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s: expected content type %q but got %q", tt.name, tt.contentType, ct)
+		}
+		if !strings.Contains(rr.Body.String(), tt.body) {
+			t.Errorf("%s: expected body to contain %q, got %s", tt.name, tt.body, rr.Body.String())
+		}
+	}
+}
+
+func TestErrorPageShowsStatus(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Home(rr, httptest.NewRequest(http.MethodGet, "/no-such-page", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d but got %d", http.StatusNotFound, rr.Code)
+	}
+	for _, want := range []string{"<h1>404 Not Found</h1>", "<p>page not found</p>"} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("Expected the error page to contain %q, got %s", want, rr.Body.String())
+		}
+	}
+}
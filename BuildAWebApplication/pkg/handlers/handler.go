@@ -1,19 +1,62 @@
 package handlers
 
 import (
+	"encoding/xml"
 	"net/http"
 
 	"github.com/kabaf81/BuildAWebApplication/pkg/render"
 )
 
 func Home(w http.ResponseWriter, r *http.Request) {
-	render.RenderTemplate(w, "home.page.tmpl.html")
+	// "/" matches every path nothing else handles
+	if r.URL.Path != "/" {
+		respondError(w, r, http.StatusNotFound, "page not found")
+		return
+	}
+	render.RenderTemplate(w, "home.page.tmpl.html", &render.TemplateData{})
 }
 
 func About(w http.ResponseWriter, r *http.Request) {
-	render.RenderTemplate(w, "about.page.tmpl.html")
+	render.RenderTemplate(w, "about.page.tmpl.html", &render.TemplateData{
+		Data: map[string]interface{}{"build": CurrentBuildInfo()},
+	})
 }
 
 func SiteMap(w http.ResponseWriter, r *http.Request) {
-	render.RenderTemplate(w, "site.page.tmpl.html")
+	render.RenderTemplate(w, "site.page.tmpl.html", &render.TemplateData{
+		Data: map[string]interface{}{"routes": Routes()},
+	})
+}
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// SiteMapXML serves the registered routes as a sitemap.xml document
+func SiteMapXML(w http.ResponseWriter, r *http.Request) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, rt := range Routes() {
+		set.URLs = append(set.URLs, sitemapURL{Loc: scheme + "://" + r.Host + rt.Path})
+	}
+
+	out, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "the site map could not be built")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(out)
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
+)
+
+func init() {
+	render.TemplatePath = "./../../templates"
+}
+
+func TestSiteMapListsRegisteredRoutes(t *testing.T) {
+	Register("/Contact", "Contact Us", Home)
+
+	rr := httptest.NewRecorder()
+	SiteMap(rr, httptest.NewRequest(http.MethodGet, "/SiteMap", nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{`href="/About"`, `href="/Contact"`, "Contact Us"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected site map to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestSiteMapXML(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SiteMapXML(rr, httptest.NewRequest(http.MethodGet, "http://example.com/sitemap.xml", nil))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/xml" {
+		t.Errorf("Expected application/xml but got %q", ct)
+	}
+	if !strings.Contains(rr.Body.String(), "<loc>http://example.com/About</loc>") {
+		t.Errorf("Expected /About in sitemap.xml, got %s", rr.Body.String())
+	}
+}
+
+func TestHome(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Home(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "<h1>Welcome</h1>") {
+		t.Errorf("Expected the home page, got %s", rr.Body.String())
+	}
+}
+
+func TestHomeUnknownPath(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Home(rr, httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d but got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestAboutShowsBuildInfo(t *testing.T) {
+	Version, Commit, BuildDate = "1.2.3", "abc1234", "2024-03-05"
+	defer func() { Version, Commit, BuildDate = "dev", "dev", "dev" }()
+
+	rr := httptest.NewRecorder()
+	About(rr, httptest.NewRequest(http.MethodGet, "/About", nil))
+
+	for _, want := range []string{"<dd>1.2.3</dd>", "<dd>abc1234</dd>", "<dd>2024-03-05</dd>"} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("Expected About to contain %q, got %s", want, rr.Body.String())
+		}
+	}
+}
+
+func TestAboutDefaultsToDev(t *testing.T) {
+	rr := httptest.NewRecorder()
+	About(rr, httptest.NewRequest(http.MethodGet, "/About", nil))
+
+	if !strings.Contains(rr.Body.String(), "<dd>dev</dd>") {
+		t.Errorf("Expected the dev defaults, got %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
+)
+
+// readyTimeout bounds each dependency check made by Readyz
+var readyTimeout = 2 * time.Second
+
+// readiness is the body served by Readyz. Checks maps each dependency to "ok"
+// or the reason it isn't ready.
+type readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Readyz reports whether the server can handle traffic: the template cache
+// must be loaded and the store reachable. It answers 503, with the failing
+// checks, when either isn't. Stores that can't be pinged are assumed ready.
+func Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	var storeErr error
+	if ps, ok := StoreFromContext(ctx).(pingingStore); ok {
+		storeErr = ps.Ping(ctx)
+	}
+	errs := map[string]error{"templates": render.CacheReady(), "store": storeErr}
+
+	res := readiness{Status: "ready", Checks: make(map[string]string, len(errs))}
+	status := http.StatusOK
+	for name, err := range errs {
+		res.Checks[name] = "ok"
+		if err != nil {
+			res.Checks[name] = err.Error()
+			res.Status = "not ready"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, res)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/config"
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
+)
+
+// downStore is a store whose database can't be reached
+type downStore struct {
+	model.UserStore
+}
+
+func (downStore) Ping(context.Context) error { return errors.New("connection refused") }
+
+func TestReadyz(t *testing.T) {
+	loaded := map[string]*template.Template{"home.page.tmpl.html": template.New("home.page.tmpl.html")}
+
+	tests := []struct {
+		name     string
+		cache    map[string]*template.Template
+		store    model.UserStore
+		status   int
+		expected map[string]string
+	}{
+		{"ready", loaded, model.MemoryStore{}, http.StatusOK, map[string]string{"templates": "ok", "store": "ok"}},
+		{"empty-cache", nil, model.MemoryStore{}, http.StatusServiceUnavailable, map[string]string{"templates": render.ErrCacheEmpty.Error(), "store": "ok"}},
+		{"store-down", loaded, downStore{}, http.StatusServiceUnavailable, map[string]string{"templates": "ok", "store": "connection refused"}},
+		{"coalesced-store-down", loaded, model.NewCoalescingStore(downStore{}, 0), http.StatusServiceUnavailable, map[string]string{"templates": "ok", "store": "connection refused"}},
+	}
+
+	defer render.NewTemplates(nil)
+	for _, tt := range tests {
+		render.NewTemplates(&config.AppConfig{TemplateCache: tt.cache})
+
+		rr := httptest.NewRecorder()
+		WithStore(tt.store)(http.HandlerFunc(Readyz)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+		}
+		var res readiness
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatalf("%s: expected a JSON body but got %v", tt.name, err)
+		}
+		for check, want := range tt.expected {
+			if res.Checks[check] != want {
+				t.Errorf("%s: expected %s check %q but got %q", tt.name, check, want, res.Checks[check])
+			}
+		}
+		if want := map[bool]string{true: "ready", false: "not ready"}[tt.status == http.StatusOK]; res.Status != want {
+			t.Errorf("%s: expected status %q but got %q", tt.name, want, res.Status)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"demo/menu"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/render"
+)
+
+// Menu returns a handler that renders m as an HTML table per section
+func Menu(m menu.Menu) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.RenderTemplate(w, "menu.page.tmpl.html", &render.TemplateData{
+			Data: map[string]interface{}{"sections": m.Sections()},
+		})
+	}
+}
+
+// menuItemJSON is one item in the /api/menu response
+type menuItemJSON struct {
+	Name     string             `json:"name"`
+	Category string             `json:"category"`
+	Prices   map[string]float64 `json:"prices"`
+}
+
+// MenuJSON returns a handler that serves m as JSON for the single-page app.
+// Items are ordered by category then name, and price keys are sorted, so the
+// output is stable.
+func MenuJSON(m menu.Menu) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+			return
+		}
+
+		items := []menuItemJSON{}
+		for _, section := range m.Sections() {
+			for _, item := range section.Items {
+				prices := make(map[string]float64, len(item.Sizes))
+				for _, size := range item.Sizes {
+					prices[size.Name] = size.Price
+				}
+				items = append(items, menuItemJSON{Name: item.Name, Category: section.Name, Prices: prices})
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string][]menuItemJSON{"items": items})
+	}
+}
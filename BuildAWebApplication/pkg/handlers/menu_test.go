@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"demo/menu"
+)
+
+func TestMenu(t *testing.T) {
+	m := menu.New([]menu.Item{
+		{Name: "Flat White", Category: "Drinks", Sizes: []menu.Size{{Name: "Regular", Price: 3.2}}},
+	})
+
+	rr := httptest.NewRecorder()
+	Menu(m)(rr, httptest.NewRequest(http.MethodGet, "/menu", nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{"<h2>Drinks</h2>", "<td>Flat White</td>", "<td>Regular</td>", "<td>$3.20</td>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected menu page to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestMenuJSON(t *testing.T) {
+	m := menu.New([]menu.Item{
+		{Name: "Tea", Category: "Drinks", Sizes: []menu.Size{{Name: "Small", Price: 1.5}, {Name: "Large", Price: 2}}},
+		{Name: "Bagel", Category: "Food", Sizes: []menu.Size{{Name: "Plain", Price: 1.8}}},
+		{Name: "Coffee", Category: "Drinks", Sizes: []menu.Size{{Name: "Regular", Price: 3.2}}},
+	})
+
+	rr := httptest.NewRecorder()
+	MenuJSON(m)(rr, httptest.NewRequest(http.MethodGet, "/api/menu", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json but got %q", ct)
+	}
+
+	want := `{"items":[` +
+		`{"name":"Coffee","category":"Drinks","prices":{"Regular":3.2}},` +
+		`{"name":"Tea","category":"Drinks","prices":{"Large":2,"Small":1.5}},` +
+		`{"name":"Bagel","category":"Food","prices":{"Plain":1.8}}]}`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Errorf("Expected %s but got %s", want, got)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"demo/menu"
+)
+
+// Route is a page served by the application and listed in the site map
+type Route struct {
+	Path    string
+	Title   string
+	Handler http.HandlerFunc
+
+	// Timeout bounds how long Handler may run. Zero means the server default.
+	Timeout time.Duration
+}
+
+var routes []Route
+
+func init() {
+	Register("/", "Home", Home)
+	Register("/About", "About", About)
+	Register("/SiteMap", "Site Map", SiteMap)
+	Register("/menu", "Menu", Menu(menu.Default()))
+}
+
+// Register adds a page to the route registry
+func Register(path, title string, handler http.HandlerFunc) {
+	routes = append(routes, Route{Path: path, Title: title, Handler: handler})
+}
+
+// RegisterWithTimeout adds a page whose handler is cut off after timeout
+func RegisterWithTimeout(path, title string, handler http.HandlerFunc, timeout time.Duration) {
+	routes = append(routes, Route{Path: path, Title: title, Handler: handler, Timeout: timeout})
+}
+
+// Routes returns the registered pages in registration order
+func Routes() []Route {
+	return append([]Route(nil), routes...)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+type jsonSchema struct {
+	Type       string                `json:"type"`
+	Properties map[string]jsonSchema `json:"properties,omitempty"`
+	Required   []string              `json:"required,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary   string                 `json:"summary"`
+	Responses map[string]openAPIResp `json:"responses"`
+}
+
+type openAPIResp struct {
+	Description string `json:"description"`
+}
+
+type openAPIDoc struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       map[string]string                      `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components map[string]map[string]jsonSchema       `json:"components"`
+}
+
+// schemaFor builds a JSON schema for a struct from its json tags
+func schemaFor(t reflect.Type) jsonSchema {
+	s := jsonSchema{Type: "object", Properties: map[string]jsonSchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		switch f.Type.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Uint, reflect.Uint64, reflect.Uint32:
+			s.Properties[name] = jsonSchema{Type: "integer"}
+		case reflect.Bool:
+			s.Properties[name] = jsonSchema{Type: "boolean"}
+		default:
+			s.Properties[name] = jsonSchema{Type: "string"}
+		}
+		s.Required = append(s.Required, name)
+	}
+	return s
+}
+
+func op(summary string, statuses ...int) openAPIOperation {
+	o := openAPIOperation{Summary: summary, Responses: map[string]openAPIResp{}}
+	for _, status := range statuses {
+		o.Responses[strconv.Itoa(status)] = openAPIResp{Description: http.StatusText(status)}
+	}
+	return o
+}
+
+// UsersSchema serves an OpenAPI description of the /users API
+func UsersSchema(w http.ResponseWriter, r *http.Request) {
+	doc := openAPIDoc{
+		OpenAPI: "3.0.3",
+		Info:    map[string]string{"title": "Users API", "version": "1.0.0"},
+		Paths: map[string]map[string]openAPIOperation{
+			"/users": {
+				"get":  op("List users, or search them with q, offset, limit and sort", http.StatusOK, http.StatusBadRequest),
+				"post": op("Create a user", http.StatusCreated, http.StatusBadRequest, http.StatusConflict),
+			},
+			"/users/count": {
+				"get": op("Count users", http.StatusOK),
+			},
+			"/users/{id}": {
+				"get":    op("Get a user", http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+				"put":    op("Update a user", http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+				"delete": op("Delete a user", http.StatusNoContent, http.StatusBadRequest, http.StatusNotFound),
+			},
+		},
+		Components: map[string]map[string]jsonSchema{
+			"schemas": {"User": schemaFor(reflect.TypeOf(model.User{}))},
+		},
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
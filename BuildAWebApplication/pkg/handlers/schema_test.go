@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUsersSchema(t *testing.T) {
+	rr := httptest.NewRecorder()
+	UsersSchema(rr, httptest.NewRequest(http.MethodGet, "/users/schema", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{`"id"`, `"firstName"`, `"lastName"`, `"/users"`, `"/users/{id}"`, `"delete"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected schema to contain %s, got %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// sortFields maps the ?sort= values to model sort fields. A leading "-"
+// reverses the order.
+var sortFields = map[string]model.SortField{
+	"id":        model.SortByID,
+	"firstName": model.SortByFirstName,
+	"lastName":  model.SortByLastName,
+}
+
+type userSearch struct {
+	q      string
+	offset int
+	limit  int
+	sortBy model.SortField
+	desc   bool
+}
+
+type userPage struct {
+	Items  []model.User `json:"items"`
+	Total  int          `json:"total"`
+	Offset int          `json:"offset"`
+	Limit  int          `json:"limit"`
+}
+
+// isSearch reports whether a GET /users asks for a search rather than the
+// plain list
+func isSearch(query url.Values) bool {
+	for _, p := range []string{"q", "offset", "limit", "sort"} {
+		if query.Has(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseUserSearch(query url.Values) (userSearch, error) {
+	s := userSearch{q: query.Get("q"), limit: defaultPageLimit}
+
+	if v := query.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return s, fmt.Errorf("offset must be a non-negative integer")
+		}
+		s.offset = n
+	}
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return s, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		s.limit = n
+	}
+	if v := query.Get("sort"); v != "" {
+		name := strings.TrimPrefix(v, "-")
+		by, ok := sortFields[name]
+		if !ok {
+			return s, fmt.Errorf("sort must be one of id, firstName or lastName, optionally prefixed with -")
+		}
+		s.sortBy, s.desc = by, name != v
+	}
+	return s, nil
+}
+
+// searchUsers serves GET /users?q=&offset=&limit=&sort= as a page of the
+// matching users: filtered by name, then sorted, then paged
+func searchUsers(w http.ResponseWriter, r *http.Request, store model.UserStore) {
+	s, err := parseUserSearch(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	users, err := store.ListUsers(r.Context())
+	if err != nil {
+		writeModelError(w, err)
+		return
+	}
+
+	matched := model.FilterUsersByName(users, s.q)
+	model.SortUsers(matched, s.sortBy, s.desc)
+
+	writeJSON(w, http.StatusOK, userPage{
+		Items:  model.PageUsers(matched, s.offset, s.limit),
+		Total:  len(matched),
+		Offset: s.offset,
+		Limit:  s.limit,
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+func TestUsersSearch(t *testing.T) {
+	store := &fakeStore{users: []model.User{
+		{ID: 1, FirstName: "Fadi", LastName: "Kaba"},
+		{ID: 2, FirstName: "John", LastName: "Smith"},
+		{ID: 3, FirstName: "Jane", LastName: "Kabir"},
+		{ID: 4, FirstName: "Amal", LastName: "Kabbani"},
+		{ID: 5, FirstName: "Zoe", LastName: "Kable"},
+	}}
+	h := WithStore(store)(http.HandlerFunc(Users))
+
+	tests := []struct {
+		query  string
+		ids    []int
+		total  int
+		offset int
+		limit  int
+	}{
+		{"?q=kab", []int{1, 3, 4, 5}, 4, 0, defaultPageLimit},
+		{"?q=kab&limit=2", []int{1, 3}, 4, 0, 2},
+		{"?q=kab&limit=2&offset=2", []int{4, 5}, 4, 2, 2},
+		{"?q=kab&sort=firstName&limit=3", []int{4, 1, 3}, 4, 0, 3},
+		{"?q=kab&sort=-firstName&offset=1&limit=2", []int{3, 1}, 4, 1, 2},
+		{"?sort=-id&limit=1", []int{5}, 5, 0, 1},
+		{"?q=kab&offset=10", []int{}, 4, 10, defaultPageLimit},
+		{"?q=nobody", []int{}, 0, 0, defaultPageLimit},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil))
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: expected status %d but got %d", tt.query, http.StatusOK, rr.Code)
+			continue
+		}
+		var page userPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatalf("%s: expected a JSON page but got %v", tt.query, err)
+		}
+
+		ids := []int{}
+		for _, u := range page.Items {
+			ids = append(ids, u.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("%s: expected IDs %v but got %v", tt.query, tt.ids, ids)
+		}
+		if page.Total != tt.total || page.Offset != tt.offset || page.Limit != tt.limit {
+			t.Errorf("%s: expected total %d, offset %d, limit %d but got %+v", tt.query, tt.total, tt.offset, tt.limit, page)
+		}
+	}
+
+	if store.users[0].ID != 1 || store.users[4].ID != 5 {
+		t.Error("Expected sorting not to reorder the store's users")
+	}
+}
+
+func TestUsersSearchInvalidParams(t *testing.T) {
+	h := WithStore(&fakeStore{})(http.HandlerFunc(Users))
+
+	for _, query := range []string{"?offset=-1", "?offset=x", "?limit=0", "?limit=101", "?limit=x", "?sort=age", "?sort=--id"} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users"+query, nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d but got %d", query, http.StatusBadRequest, rr.Code)
+		}
+	}
+}
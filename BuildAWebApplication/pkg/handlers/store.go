@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+type storeKey struct{}
+
+// WithStore makes store available to handlers through StoreFromContext
+func WithStore(store model.UserStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), storeKey{}, store)))
+		})
+	}
+}
+
+// StoreFromContext returns the store set by WithStore, or the in-memory store
+// when there is none
+func StoreFromContext(ctx context.Context) model.UserStore {
+	if store, ok := ctx.Value(storeKey{}).(model.UserStore); ok {
+		return store
+	}
+	return model.MemoryStore{}
+}
+
+// idempotentStore is implemented by stores that support Idempotency-Key
+type idempotentStore interface {
+	AddUserIdempotent(ctx context.Context, key string, u model.User) (model.User, bool, error)
+}
+
+// pingingStore is implemented by stores that can check they are reachable
+// without doing any real work
+type pingingStore interface {
+	Ping(ctx context.Context) error
+}
+
+// countingStore is implemented by stores that can count without listing
+type countingStore interface {
+	CountUsers(ctx context.Context) (int, error)
+}
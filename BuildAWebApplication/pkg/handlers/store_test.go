@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+// fakeStore serves a fixed list of users and records the users added to it
+type fakeStore struct {
+	model.UserStore
+	users []model.User
+	added []model.User
+}
+
+func (s *fakeStore) ListUsers(context.Context) ([]model.User, error) { return s.users, nil }
+
+func (s *fakeStore) AddUser(_ context.Context, u model.User) (model.User, error) {
+	u.ID = 100 + len(s.added)
+	s.added = append(s.added, u)
+	return u, nil
+}
+
+func TestUsersUsesStoreFromContext(t *testing.T) {
+	store := &fakeStore{users: []model.User{{ID: 7, FirstName: "Fake", LastName: "User"}}}
+	h := WithStore(store)(http.HandlerFunc(Users))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	var got []model.User
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Expected valid JSON but got %v", err)
+	}
+	if len(got) != 1 || got[0].FirstName != "Fake" {
+		t.Errorf("Expected the fake store's users but got %v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Fadi","lastName":"Kaba"}`)))
+	if rr.Code != http.StatusCreated || len(store.added) != 1 {
+		t.Errorf("Expected the user to be added to the fake store, got %d and %v", rr.Code, store.added)
+	}
+
+	// the fake store has no idempotency support
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Fadi","lastName":"Kaba"}`))
+	req.Header.Set("Idempotency-Key", "abc")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status %d but got %d", http.StatusNotImplemented, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	WithStore(store)(http.HandlerFunc(UsersCount)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/count", nil))
+	if body := strings.TrimSpace(rr.Body.String()); body != `{"count":1}` {
+		t.Errorf("Expected the count to come from the fake store, got %s", body)
+	}
+}
+
+func TestStoreFromContextDefault(t *testing.T) {
+	if _, ok := StoreFromContext(context.Background()).(model.MemoryStore); !ok {
+		t.Error("Expected the in-memory store when none is set")
+	}
+}
+
+// countOnlyStore is the in-memory store with listing broken, to show a count
+// never falls back to listing
+type countOnlyStore struct {
+	model.MemoryStore
+}
+
+func (countOnlyStore) ListUsers(context.Context) ([]model.User, error) {
+	return nil, errors.New("listing not allowed")
+}
+
+func TestCoalescingStoreKeepsOptionalFeatures(t *testing.T) {
+	h := WithStore(model.NewCoalescingStore(countOnlyStore{}, time.Second))
+	key := fmt.Sprintf("coalesce-key-%d", time.Now().UnixNano())
+
+	var ids []int
+	for _, status := range []int{http.StatusCreated, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Nora","lastName":"Haddad"}`))
+		req.Header.Set("Idempotency-Key", key)
+		rr := httptest.NewRecorder()
+		h(http.HandlerFunc(Users)).ServeHTTP(rr, req)
+
+		if rr.Code != status {
+			t.Fatalf("Expected status %d but got %d: %s", status, rr.Code, rr.Body.String())
+		}
+		var u model.User
+		_ = json.NewDecoder(rr.Body).Decode(&u)
+		ids = append(ids, u.ID)
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("Expected the replay to return the same user, got IDs %v", ids)
+	}
+
+	rr := httptest.NewRecorder()
+	h(http.HandlerFunc(UsersCount)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/count", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil || got.Count != model.CountUsers() {
+		t.Errorf("Expected count %d but got %d (%v)", model.CountUsers(), got.Count, err)
+	}
+
+	// a wrapped store without idempotency keys still reports 501
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Nora","lastName":"Haddad"}`))
+	req.Header.Set("Idempotency-Key", key)
+	rr = httptest.NewRecorder()
+	WithStore(model.NewCoalescingStore(&fakeStore{}, time.Second))(http.HandlerFunc(Users)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status %d but got %d", http.StatusNotImplemented, rr.Code)
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+var errBadID = errors.New("id must be a positive integer")
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// streamFlushEvery is how many users streamUsers writes between flushes
+const streamFlushEvery = 100
+
+// streamUsers writes us as a JSON array one element at a time, so a large list
+// is never marshaled into a single buffer. The response is flushed as it goes
+// when w supports it.
+func streamUsers(w http.ResponseWriter, us []model.User) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	_, _ = io.WriteString(w, "[")
+	for i, u := range us {
+		if i > 0 {
+			_, _ = io.WriteString(w, ",")
+		}
+		if err := enc.Encode(u); err != nil {
+			return
+		}
+		if flusher != nil && (i+1)%streamFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	_, _ = io.WriteString(w, "]\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// decodeJSON decodes the request body into v, writing a 413 or 400 response
+// and returning false when it can't
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: http.StatusText(http.StatusRequestEntityTooLarge)})
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON body"})
+	return false
+}
+
+// Users serves GET (list, or search with query params) and POST (create) on
+// /users
+func Users(w http.ResponseWriter, r *http.Request) {
+	store := StoreFromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		if isSearch(r.URL.Query()) {
+			searchUsers(w, r, store)
+			return
+		}
+
+		users, err := store.ListUsers(r.Context())
+		if err != nil {
+			writeModelError(w, err)
+			return
+		}
+		streamUsers(w, users)
+
+	case http.MethodPost:
+		var u model.User
+		if !decodeJSON(w, r, &u) {
+			return
+		}
+
+		// A repeated Idempotency-Key returns the user it created the first time
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			is, ok := store.(idempotentStore)
+			if !ok {
+				writeJSON(w, http.StatusNotImplemented, errorResponse{Error: "Idempotency-Key is not supported by this store"})
+				return
+			}
+			existing, created, err := is.AddUserIdempotent(r.Context(), key, u)
+			if err != nil {
+				writeModelError(w, err)
+				return
+			}
+			status := http.StatusCreated
+			if !created {
+				status = http.StatusOK
+			}
+			writeJSON(w, status, existing)
+			return
+		}
+
+		created, err := store.AddUser(r.Context(), u)
+		if err != nil {
+			writeModelError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+	}
+}
+
+// UsersCount serves GET /users/count as {"count": N}
+func UsersCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+	count, err := countUsers(r.Context(), StoreFromContext(r.Context()))
+	if err != nil {
+		writeModelError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"count": count})
+}
+
+// countUsers counts without listing when the store supports it
+func countUsers(ctx context.Context, store model.UserStore) (int, error) {
+	if cs, ok := store.(countingStore); ok {
+		return cs.CountUsers(ctx)
+	}
+	users, err := store.ListUsers(ctx)
+	return len(users), err
+}
+
+// UserByID serves GET, PUT (update) and DELETE on /users/{id}
+func UserByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r.URL.Path)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	store := StoreFromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		u, err := store.GetUser(r.Context(), id)
+		if err != nil {
+			writeModelError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, u)
+
+	case http.MethodPut:
+		var u model.User
+		if !decodeJSON(w, r, &u) {
+			return
+		}
+		updated, err := store.UpdateUser(r.Context(), id, u)
+		if err != nil {
+			writeModelError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+
+	case http.MethodDelete:
+		if err := store.DeleteUser(r.Context(), id); err != nil {
+			writeModelError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+	}
+}
+
+// userID parses the {id} segment of a /users/{id} path
+func userID(path string) (int, error) {
+	segment := strings.Trim(strings.TrimPrefix(path, "/users/"), "/")
+	id, err := strconv.Atoi(segment)
+	if err != nil || id < 1 || strings.Contains(segment, "/") {
+		return 0, errBadID
+	}
+	return id, nil
+}
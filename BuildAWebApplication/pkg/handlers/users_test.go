@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/model"
+)
+
+func TestUserByID(t *testing.T) {
+	toUpdate, _ := model.AddUser(model.User{FirstName: "Fadi", LastName: "Kaba"})
+	toDelete, _ := model.AddUser(model.User{FirstName: "John", LastName: "Smith"})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"update", http.MethodPut, "/users/" + strconv.Itoa(toUpdate.ID), `{"firstName":"Jane","lastName":"Doe"}`, http.StatusOK},
+		{"update-invalid", http.MethodPut, "/users/" + strconv.Itoa(toUpdate.ID), `{"firstName":"Jane"}`, http.StatusBadRequest},
+		{"delete", http.MethodDelete, "/users/" + strconv.Itoa(toDelete.ID), "", http.StatusNoContent},
+		{"delete-unknown", http.MethodDelete, "/users/999999", "", http.StatusNotFound},
+		{"update-unknown", http.MethodPut, "/users/999999", `{"firstName":"Jane","lastName":"Doe"}`, http.StatusNotFound},
+		{"bad-id", http.MethodPut, "/users/abc", `{"firstName":"Jane","lastName":"Doe"}`, http.StatusBadRequest},
+		{"negative-id", http.MethodDelete, "/users/-1", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		UserByID(rr, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d (%s)", tt.name, tt.status, rr.Code, rr.Body.String())
+		}
+	}
+
+	got, err := model.GetUser(toUpdate.ID)
+	if err != nil || got.FirstName != "Jane" || got.LastName != "Doe" {
+		t.Errorf("Expected the user to be updated, got %+v (%v)", got, err)
+	}
+	if _, err := model.GetUser(toDelete.ID); err == nil {
+		t.Error("Expected the deleted user to be gone")
+	}
+}
+
+func TestUsersCreate(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Users(rr, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Li","lastName":"Chen"}`)))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d but got %d", http.StatusCreated, rr.Code)
+	}
+	var u model.User
+	if err := json.NewDecoder(rr.Body).Decode(&u); err != nil || u.ID == 0 {
+		t.Errorf("Expected the created user with an ID, got %+v (%v)", u, err)
+	}
+}
+
+func TestUsersCreateIdempotencyKey(t *testing.T) {
+	// keys are remembered by the shared store, so each run needs its own
+	key := fmt.Sprintf("handler-key-%d", time.Now().UnixNano())
+
+	var ids []int
+	for _, status := range []int{http.StatusCreated, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Sara","lastName":"Khan"}`))
+		req.Header.Set("Idempotency-Key", key)
+		rr := httptest.NewRecorder()
+		Users(rr, req)
+
+		if rr.Code != status {
+			t.Errorf("Expected status %d but got %d", status, rr.Code)
+		}
+		var u model.User
+		_ = json.NewDecoder(rr.Body).Decode(&u)
+		ids = append(ids, u.ID)
+	}
+
+	if ids[0] != ids[1] {
+		t.Errorf("Expected the replay to return the same user, got IDs %v", ids)
+	}
+}
+
+func TestUsersCount(t *testing.T) {
+	_, _ = model.AddUser(model.User{FirstName: "Fadi", LastName: "Kaba"})
+
+	rr := httptest.NewRecorder()
+	UsersCount(rr, httptest.NewRequest(http.MethodGet, "/users/count", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+	var got struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if want := model.CountUsers(); got.Count != want {
+		t.Errorf("Expected count %d but got %d", want, got.Count)
+	}
+
+	rr = httptest.NewRecorder()
+	UsersCount(rr, httptest.NewRequest(http.MethodPost, "/users/count", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d but got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+}
+
+func TestUsersListStreams(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		_, _ = model.AddUser(model.User{FirstName: "Fadi", LastName: "Kaba"})
+	}
+
+	rr := httptest.NewRecorder()
+	Users(rr, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json but got %q", ct)
+	}
+	if !rr.Flushed {
+		t.Error("Expected the response to be flushed while streaming")
+	}
+
+	var got []model.User
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Expected valid JSON but got %v", err)
+	}
+	if want := model.CountUsers(); len(got) != want {
+		t.Errorf("Expected %d users but got %d", want, len(got))
+	}
+
+	rr = httptest.NewRecorder()
+	streamUsers(rr, nil)
+	if body := strings.TrimSpace(rr.Body.String()); body != "[]" {
+		t.Errorf("Expected an empty array but got %q", body)
+	}
+}
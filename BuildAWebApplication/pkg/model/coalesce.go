@@ -0,0 +1,114 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CoalescingStore wraps a UserStore so that adding a user with the same name
+// as one added moments ago, such as from a double-clicked submit button,
+// returns the earlier user instead of inserting a second one. Names match
+// ignoring case and surrounding whitespace. Every other method goes straight
+// to the wrapped store, including the optional Ping, CountUsers and
+// AddUserIdempotent.
+type CoalescingStore struct {
+	UserStore
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	recent map[string]*coalescedAdd
+}
+
+// coalescedAdd is an insert that later duplicates wait on and then share
+type coalescedAdd struct {
+	done chan struct{}
+	user User
+	err  error
+	at   time.Time // zero while the insert is in flight
+}
+
+// NewCoalescingStore returns store with duplicate adds within window coalesced
+func NewCoalescingStore(store UserStore, window time.Duration) *CoalescingStore {
+	return &CoalescingStore{UserStore: store, window: window, now: time.Now, recent: map[string]*coalescedAdd{}}
+}
+
+// AddUser adds u, or returns the user added for the same name within the
+// window. A duplicate that arrives while the first insert is still running
+// waits for it and gets its result. Failed inserts are not remembered.
+func (s *CoalescingStore) AddUser(ctx context.Context, u User) (User, error) {
+	key := strings.ToLower(strings.TrimSpace(u.FirstName)) + "\x00" + strings.ToLower(strings.TrimSpace(u.LastName))
+
+	s.mu.Lock()
+	s.expire()
+	if c, ok := s.recent[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.user, c.err
+		case <-ctx.Done():
+			return User{}, ctx.Err()
+		}
+	}
+	c := &coalescedAdd{done: make(chan struct{})}
+	s.recent[key] = c
+	s.mu.Unlock()
+
+	c.user, c.err = s.UserStore.AddUser(ctx, u)
+
+	s.mu.Lock()
+	c.at = s.now()
+	if c.err != nil {
+		delete(s.recent, key)
+	}
+	s.mu.Unlock()
+	close(c.done)
+
+	return c.user, c.err
+}
+
+// AddUserIdempotent passes straight to the wrapped store, without coalescing:
+// the key already makes repeats return the first user. It returns
+// ErrUnsupported if the wrapped store has no idempotency keys.
+func (s *CoalescingStore) AddUserIdempotent(ctx context.Context, key string, u User) (User, bool, error) {
+	is, ok := s.UserStore.(interface {
+		AddUserIdempotent(ctx context.Context, key string, u User) (User, bool, error)
+	})
+	if !ok {
+		return User{}, false, fmt.Errorf("idempotency keys: %w", ErrUnsupported)
+	}
+	return is.AddUserIdempotent(ctx, key, u)
+}
+
+// CountUsers counts with the wrapped store, listing its users if it can't
+// count them directly
+func (s *CoalescingStore) CountUsers(ctx context.Context) (int, error) {
+	if cs, ok := s.UserStore.(interface {
+		CountUsers(context.Context) (int, error)
+	}); ok {
+		return cs.CountUsers(ctx)
+	}
+	users, err := s.UserStore.ListUsers(ctx)
+	return len(users), err
+}
+
+// Ping pings the wrapped store if it supports it, and otherwise succeeds
+func (s *CoalescingStore) Ping(ctx context.Context) error {
+	if p, ok := s.UserStore.(interface{ Ping(context.Context) error }); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// expire forgets finished inserts older than the window. s.mu must be held.
+func (s *CoalescingStore) expire() {
+	now := s.now()
+	for key, c := range s.recent {
+		if !c.at.IsZero() && now.Sub(c.at) >= s.window {
+			delete(s.recent, key)
+		}
+	}
+}
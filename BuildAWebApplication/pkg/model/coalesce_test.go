@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoalescingStore(t *testing.T) {
+	resetUsers()
+
+	now := time.Now()
+	s := NewCoalescingStore(MemoryStore{}, time.Second)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	first, err := s.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	second, err := s.AddUser(ctx, User{FirstName: " fadi", LastName: "KABA"})
+	if err != nil || second.ID != first.ID {
+		t.Errorf("Expected the duplicate to return user %d but got %+v (%v)", first.ID, second, err)
+	}
+	if n := CountUsers(); n != 1 {
+		t.Errorf("Expected a single insert but got %d users", n)
+	}
+
+	if other, _ := s.AddUser(ctx, User{FirstName: "John", LastName: "Smith"}); other.ID == first.ID {
+		t.Error("Expected a different name to be inserted")
+	}
+
+	now = now.Add(time.Second)
+	if later, _ := s.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"}); later.ID == first.ID {
+		t.Error("Expected the same name after the window to be inserted again")
+	}
+	if n := CountUsers(); n != 3 {
+		t.Errorf("Expected 3 users but got %d", n)
+	}
+}
+
+// blockingStore holds every AddUser until release is closed
+type blockingStore struct {
+	MemoryStore
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s blockingStore) AddUser(ctx context.Context, u User) (User, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return s.MemoryStore.AddUser(ctx, u)
+}
+
+func TestCoalescingStoreConcurrent(t *testing.T) {
+	resetUsers()
+
+	inner := blockingStore{started: make(chan struct{}, 2), release: make(chan struct{})}
+	s := NewCoalescingStore(inner, time.Second)
+
+	var wg sync.WaitGroup
+	ids := make([]int, 2)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u, _ := s.AddUser(context.Background(), User{FirstName: "Fadi", LastName: "Kaba"})
+			ids[i] = u.ID
+		}(i)
+	}
+
+	<-inner.started
+	// give the second call time to find the first one in flight
+	time.Sleep(20 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if ids[0] != ids[1] || CountUsers() != 1 {
+		t.Errorf("Expected both calls to share one insert, got IDs %v and %d users", ids, CountUsers())
+	}
+}
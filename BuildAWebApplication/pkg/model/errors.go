@@ -0,0 +1,36 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUserNotFound is returned when no user has the requested ID
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrDuplicate is returned when a user clashes with one already stored
+	ErrDuplicate = errors.New("duplicate user")
+
+	// ErrUnsupported is returned by a wrapping store when the store it wraps
+	// lacks an optional feature, such as idempotency keys
+	ErrUnsupported = errors.New("not supported by this store")
+)
+
+// ErrValidation reports the field that made a user invalid. Match it with
+// errors.As to read the field, or errors.Is against a zero ErrValidation.
+type ErrValidation struct {
+	Field  string
+	Reason string
+}
+
+func (e *ErrValidation) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Is reports any *ErrValidation as a match so errors.Is(err, &ErrValidation{})
+// works regardless of the field.
+func (e *ErrValidation) Is(target error) bool {
+	_, ok := target.(*ErrValidation)
+	return ok
+}
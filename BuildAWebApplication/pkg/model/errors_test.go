@@ -0,0 +1,46 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAddUserValidationError(t *testing.T) {
+	resetUsers()
+
+	_, err := AddUser(User{FirstName: "Fadi"})
+
+	var verr *ErrValidation
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected an ErrValidation but got %v", err)
+	}
+	if verr.Field != "lastName" {
+		t.Errorf("Expected field lastName but got %s", verr.Field)
+	}
+	if !errors.Is(err, &ErrValidation{}) {
+		t.Error("Expected errors.Is to match ErrValidation")
+	}
+}
+
+func TestErrorsIsThroughWrapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"not-found", fmt.Errorf("get user 7: %w", ErrUserNotFound), ErrUserNotFound},
+		{"duplicate", fmt.Errorf("add user: %w", ErrDuplicate), ErrDuplicate},
+		{"validation", fmt.Errorf("add user: %w", &ErrValidation{Field: "firstName"}), &ErrValidation{}},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.target) {
+			t.Errorf("%s: expected errors.Is to match", tt.name)
+		}
+	}
+
+	if errors.Is(ErrUserNotFound, ErrDuplicate) {
+		t.Error("Expected ErrUserNotFound not to match ErrDuplicate")
+	}
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is a UserStore that keeps its users in a JSON file. The file is
+// read once by NewFileStore and rewritten after every change.
+type FileStore struct {
+	mu    sync.Mutex
+	path  string
+	users []User
+	ids   IDGenerator
+	// nextID is one past the highest ID handed out, saved so the default
+	// generator carries on from it when the file is reopened
+	nextID int
+}
+
+var _ UserStore = (*FileStore)(nil)
+
+// fileData is the JSON layout of a FileStore's file
+type fileData struct {
+	NextID int    `json:"nextId"`
+	Users  []User `json:"users"`
+}
+
+// NewFileStore opens the store saved at path. A missing file gives an empty
+// store; the file is created on the first change.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, ids: &SequentialIDs{}, nextID: 1}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fd fileData
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.users = fd.Users
+	if fd.NextID > s.nextID {
+		s.nextID = fd.NextID
+	}
+	s.ids = &SequentialIDs{last: s.nextID - 1}
+	return s, nil
+}
+
+// SetIDGenerator sets the generator used for new users; nil restores a
+// SequentialIDs that carries on from the highest ID handed out so far.
+func (s *FileStore) SetIDGenerator(g IDGenerator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if g == nil {
+		g = &SequentialIDs{last: s.nextID - 1}
+	}
+	s.ids = g
+}
+
+func (s *FileStore) AddUser(_ context.Context, u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u.ID = s.ids.Next()
+	if s.indexOf(u.ID) >= 0 {
+		return User{}, fmt.Errorf("user %d: %w", u.ID, ErrDuplicate)
+	}
+	oldNext := s.nextID
+	if u.ID >= s.nextID {
+		s.nextID = u.ID + 1
+	}
+	s.users = append(s.users, u)
+	if err := s.save(); err != nil {
+		s.users = s.users[:len(s.users)-1]
+		s.nextID = oldNext
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *FileStore) GetUser(_ context.Context, id int) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	return s.users[i], nil
+}
+
+func (s *FileStore) ListUsers(context.Context) ([]User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]User{}, s.users...), nil
+}
+
+func (s *FileStore) UpdateUser(_ context.Context, id int, u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	old := s.users[i]
+	u.ID = id
+	s.users[i] = u
+	if err := s.save(); err != nil {
+		s.users[i] = old
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *FileStore) DeleteUser(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	old := s.users
+	s.users = append(append([]User{}, s.users[:i]...), s.users[i+1:]...)
+	if err := s.save(); err != nil {
+		s.users = old
+		return err
+	}
+	return nil
+}
+
+// indexOf returns the position of the user with the given ID, or -1.
+// s.mu must be held.
+func (s *FileStore) indexOf(id int) int {
+	for i, u := range s.users {
+		if u.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// save writes the store to a temporary file and renames it over s.path, so a
+// crash mid-write never leaves a truncated file. s.mu must be held.
+func (s *FileStore) save() error {
+	data, err := json.MarshalIndent(fileData{NextID: s.nextID, Users: s.users}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
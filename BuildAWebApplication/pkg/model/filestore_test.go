@@ -0,0 +1,109 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileStorePersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	fadi, _ := s.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"})
+	john, _ := s.AddUser(ctx, User{FirstName: "John", LastName: "Smith"})
+	jane, _ := s.AddUser(ctx, User{FirstName: "Jane", LastName: "Doe"})
+	if _, err := s.UpdateUser(ctx, john.ID, User{FirstName: "Johnny", LastName: "Smith"}); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if err := s.DeleteUser(ctx, fadi.ID); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if _, err := s.AddUser(ctx, User{FirstName: "", LastName: "Invalid"}); !errors.Is(err, &ErrValidation{}) {
+		t.Errorf("Expected a validation error but got %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	got, _ := reopened.ListUsers(ctx)
+	want := []User{{ID: john.ID, FirstName: "Johnny", LastName: "Smith"}, jane}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v but got %v", want, got)
+	}
+
+	// IDs keep counting from where the first store left off
+	next, _ := reopened.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"})
+	if next.ID != jane.ID+1 {
+		t.Errorf("Expected ID %d but got %d", jane.ID+1, next.ID)
+	}
+
+	if _, err := reopened.GetUser(ctx, fadi.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound but got %v", err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the store file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestFileStoreIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	s.SetIDGenerator(&fixedIDs{1001, 42, 42})
+
+	for _, want := range []int{1001, 42} {
+		u, err := s.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"})
+		if err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+		if u.ID != want {
+			t.Errorf("Expected ID %d but got %d", want, u.ID)
+		}
+	}
+	if _, err := s.AddUser(ctx, User{FirstName: "John", LastName: "Smith"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate for a reused ID but got %v", err)
+	}
+
+	// the default generator carries on past the highest injected ID
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if u, _ := reopened.AddUser(ctx, User{FirstName: "Jane", LastName: "Doe"}); u.ID != 1002 {
+		t.Errorf("Expected ID 1002 but got %d", u.ID)
+	}
+}
+
+func TestNewFileStoreErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Expected a missing file to give an empty store, got %v", err)
+	}
+	if users, _ := s.ListUsers(context.Background()); len(users) != 0 {
+		t.Errorf("Expected no users but got %v", users)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("Expected an error for a malformed file")
+	}
+}
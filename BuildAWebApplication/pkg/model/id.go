@@ -0,0 +1,36 @@
+package model
+
+import "sync"
+
+// IDGenerator hands out IDs for new users. Next is called with the store's
+// lock held, so an implementation only needs its own locking if it is shared
+// with something else.
+type IDGenerator interface {
+	Next() int
+}
+
+// SequentialIDs counts up from 1. It is the store's default generator.
+type SequentialIDs struct {
+	mu   sync.Mutex
+	last int
+}
+
+func (g *SequentialIDs) Next() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.last++
+	return g.last
+}
+
+// SetIDGenerator sets the generator used by the in-memory store; nil restores
+// a fresh SequentialIDs. Users already stored keep their IDs. A FileStore has
+// its own generator, see FileStore.SetIDGenerator.
+func SetIDGenerator(g IDGenerator) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if g == nil {
+		g = &SequentialIDs{}
+	}
+	ids = g
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+// fixedIDs hands out the given IDs in order
+type fixedIDs []int
+
+func (f *fixedIDs) Next() int {
+	id := (*f)[0]
+	*f = (*f)[1:]
+	return id
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	resetUsers()
+	defer SetIDGenerator(nil)
+
+	SetIDGenerator(&fixedIDs{1001, 42, 42})
+
+	for _, want := range []int{1001, 42} {
+		u, err := AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+		if err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+		if u.ID != want {
+			t.Errorf("Expected ID %d but got %d", want, u.ID)
+		}
+	}
+	if _, err := GetUser(1001); err != nil {
+		t.Errorf("Expected the user to be stored under the injected ID, got %v", err)
+	}
+
+	if _, err := AddUser(User{FirstName: "John", LastName: "Smith"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate for a reused ID but got %v", err)
+	}
+	if CountUsers() != 2 {
+		t.Errorf("Expected 2 users but got %d", CountUsers())
+	}
+
+	SetIDGenerator(nil)
+	if u, _ := AddUser(User{FirstName: "Jane", LastName: "Doe"}); u.ID != 1 {
+		t.Errorf("Expected a fresh sequential generator to start at 1, got %d", u.ID)
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+// AddUserIdempotent adds u unless key has been used before, in which case it
+// returns the user that key created and created is false. This lets clients
+// retry a create without risking a duplicate. If the earlier user has since
+// been deleted, the key creates a new one.
+func AddUserIdempotent(key string, u User) (User, bool, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, false, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id, ok := idempotencyKeys[key]; ok {
+		if i := indexOf(id); i >= 0 {
+			logger.Debug("idempotent replay", "key", key, "id", id)
+			return *users[i], false, nil
+		}
+	}
+
+	created, err := addUser(u)
+	if err != nil {
+		return User{}, false, err
+	}
+	idempotencyKeys[key] = created.ID
+	return created, true, nil
+}
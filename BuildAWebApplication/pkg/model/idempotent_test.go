@@ -0,0 +1,28 @@
+package model
+
+import "testing"
+
+func TestAddUserIdempotent(t *testing.T) {
+	resetUsers()
+
+	first, created, err := AddUserIdempotent("req-1", User{FirstName: "Fadi", LastName: "Kaba"})
+	if err != nil || !created {
+		t.Fatalf("Expected the first insert to create a user, got created=%v err=%v", created, err)
+	}
+
+	replay, created, err := AddUserIdempotent("req-1", User{FirstName: "Fadi", LastName: "Kaba"})
+	if err != nil || created {
+		t.Errorf("Expected the replay not to create a user, got created=%v err=%v", created, err)
+	}
+	if replay.ID != first.ID {
+		t.Errorf("Expected the replay to return ID %d but got %d", first.ID, replay.ID)
+	}
+	if len(GetUsers()) != 1 {
+		t.Errorf("Expected 1 stored user but got %d", len(GetUsers()))
+	}
+
+	other, created, _ := AddUserIdempotent("req-2", User{FirstName: "Fadi", LastName: "Kaba"})
+	if !created || other.ID == first.ID {
+		t.Errorf("Expected a new key to create a new user, got %+v created=%v", other, created)
+	}
+}
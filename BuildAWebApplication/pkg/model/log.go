@@ -0,0 +1,21 @@
+package model
+
+import (
+	"io"
+	"log/slog"
+)
+
+// logger receives debug output from store operations. It discards everything
+// until SetLogger is called, so importing the package stays quiet.
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// SetLogger sets the logger used by store operations; nil restores the no-op logger
+func SetLogger(l *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l == nil {
+		l = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	logger = l
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	resetUsers()
+
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	u, _ := AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+
+	line := buf.String()
+	if !strings.Contains(line, "level=DEBUG") || !strings.Contains(line, `msg="user added"`) || !strings.Contains(line, "id=1") {
+		t.Errorf("Expected a debug line for user %d, got %q", u.ID, line)
+	}
+}
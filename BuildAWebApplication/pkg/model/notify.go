@@ -0,0 +1,25 @@
+package model
+
+// Notifier is told about users added to the store
+type Notifier interface {
+	UserAdded(u User)
+}
+
+// notifiers are called after every successful add. Guarded by mu.
+var notifiers []Notifier
+
+// AddNotifier registers n to be called after each user is added. Each call is
+// made in its own goroutine, so a slow notifier never blocks the caller and
+// notifications may arrive out of order.
+func AddNotifier(n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+	notifiers = append(notifiers, n)
+}
+
+// notifyAdded dispatches u to every notifier. mu must be held.
+func notifyAdded(u User) {
+	for _, n := range notifiers {
+		go n.UserAdded(u)
+	}
+}
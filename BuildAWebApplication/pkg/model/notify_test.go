@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// recordingNotifier sends every user it is told about on a channel
+type recordingNotifier chan User
+
+func (n recordingNotifier) UserAdded(u User) { n <- u }
+
+func TestNotifierCalledOnAdd(t *testing.T) {
+	resetUsers()
+	rec := make(recordingNotifier, 2)
+	AddNotifier(rec)
+
+	created, _ := AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+	_, _ = AddUser(User{FirstName: "", LastName: "Invalid"})
+
+	select {
+	case got := <-rec:
+		if got != created {
+			t.Errorf("Expected %+v but got %+v", created, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the notifier to be called")
+	}
+
+	select {
+	case got := <-rec:
+		t.Errorf("Expected no notification for an invalid user, got %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestNotifierDoesNotBlock(t *testing.T) {
+	resetUsers()
+	rec := make(recordingNotifier)
+	AddNotifier(rec)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected AddUser to return while the notifier is blocked")
+	}
+	<-rec
+}
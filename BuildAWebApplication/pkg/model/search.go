@@ -0,0 +1,45 @@
+package model
+
+import "strings"
+
+// FindUsersByName returns the stored users whose first or last name contains
+// q, ignoring case. An empty q matches everyone.
+func FindUsersByName(q string) []User {
+	return FilterUsersByName(GetUsers(), q)
+}
+
+// GetUsersPage returns at most limit stored users starting at offset, in ID
+// order
+func GetUsersPage(offset, limit int) []User {
+	return PageUsers(GetUsers(), offset, limit)
+}
+
+// FilterUsersByName returns the users in us that FindUsersByName would match,
+// in a new slice that can be reordered without touching us
+func FilterUsersByName(us []User, q string) []User {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return append([]User(nil), us...)
+	}
+
+	var found []User
+	for _, u := range us {
+		if strings.Contains(strings.ToLower(u.FirstName), q) || strings.Contains(strings.ToLower(u.LastName), q) {
+			found = append(found, u)
+		}
+	}
+	return found
+}
+
+// PageUsers returns the window of us starting at offset and holding at most
+// limit users. Out-of-range windows are empty rather than an error.
+func PageUsers(us []User, offset, limit int) []User {
+	if offset < 0 || offset >= len(us) || limit <= 0 {
+		return []User{}
+	}
+	end := offset + limit
+	if end > len(us) {
+		end = len(us)
+	}
+	return us[offset:end]
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindUsersByName(t *testing.T) {
+	resetUsers()
+	for _, u := range []User{
+		{FirstName: "Fadi", LastName: "Kaba"},
+		{FirstName: "John", LastName: "Smith"},
+		{FirstName: "Jane", LastName: "Kabir"},
+	} {
+		_, _ = AddUser(u)
+	}
+
+	tests := []struct {
+		q    string
+		want []int
+	}{
+		{"kab", []int{1, 3}},
+		{"JO", []int{2}},
+		{"", []int{1, 2, 3}},
+		{"nobody", nil},
+	}
+
+	for _, tt := range tests {
+		var got []int
+		for _, u := range FindUsersByName(tt.q) {
+			got = append(got, u.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected IDs %v but got %v", tt.q, tt.want, got)
+		}
+	}
+}
+
+func TestGetUsersPage(t *testing.T) {
+	resetUsers()
+	for i := 0; i < 5; i++ {
+		_, _ = AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+	}
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []int
+	}{
+		{"first", 0, 2, []int{1, 2}},
+		{"middle", 2, 2, []int{3, 4}},
+		{"short-last", 4, 2, []int{5}},
+		{"past-end", 5, 2, nil},
+		{"zero-limit", 0, 0, nil},
+	}
+
+	for _, tt := range tests {
+		var got []int
+		for _, u := range GetUsersPage(tt.offset, tt.limit) {
+			got = append(got, u.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected IDs %v but got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import "math/rand"
+
+var (
+	seedFirstNames = []string{"Fadi", "John", "Jane", "Maria", "Ahmed", "Li", "Sara", "Tom", "Nina", "Omar"}
+	seedLastNames  = []string{"Kaba", "Smith", "Doe", "Garcia", "Chen", "Brown", "Khan", "Taylor", "Nguyen", "Wilson"}
+)
+
+// SeedUsers adds n users with random names picked by r and returns them.
+// Passing a rand.Rand with a fixed seed makes the output reproducible.
+func SeedUsers(n int, r *rand.Rand) []User {
+	seeded := make([]User, 0, n)
+	for i := 0; i < n; i++ {
+		u, err := AddUser(User{
+			FirstName: seedFirstNames[r.Intn(len(seedFirstNames))],
+			LastName:  seedLastNames[r.Intn(len(seedLastNames))],
+		})
+		if err != nil {
+			continue
+		}
+		seeded = append(seeded, u)
+	}
+	return seeded
+}
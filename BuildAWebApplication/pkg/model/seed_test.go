@@ -0,0 +1,25 @@
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSeedUsers(t *testing.T) {
+	resetUsers()
+	first := SeedUsers(20, rand.New(rand.NewSource(42)))
+
+	resetUsers()
+	second := SeedUsers(20, rand.New(rand.NewSource(42)))
+
+	if len(first) != 20 {
+		t.Fatalf("Expected 20 users but got %d", len(first))
+	}
+	if len(GetUsers()) != 20 {
+		t.Errorf("Expected 20 stored users but got %d", len(GetUsers()))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same users for the same seed")
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortField selects the field GetUsersSorted orders by
+type SortField int
+
+const (
+	SortByID SortField = iota
+	SortByFirstName
+	SortByLastName
+)
+
+// GetUsersSorted returns a copy of the stored users ordered by the given field,
+// descending when desc is true. Names compare case-insensitively; users with
+// equal names keep their insertion order.
+func GetUsersSorted(by SortField, desc bool) []User {
+	us := GetUsers()
+	SortUsers(us, by, desc)
+	return us
+}
+
+// SortUsers orders us in place the same way as GetUsersSorted
+func SortUsers(us []User, by SortField, desc bool) {
+	var less func(a, b User) bool
+	switch by {
+	case SortByFirstName:
+		less = func(a, b User) bool { return strings.ToLower(a.FirstName) < strings.ToLower(b.FirstName) }
+	case SortByLastName:
+		less = func(a, b User) bool { return strings.ToLower(a.LastName) < strings.ToLower(b.LastName) }
+	default:
+		less = func(a, b User) bool { return a.ID < b.ID }
+	}
+
+	sort.SliceStable(us, func(i, j int) bool {
+		if desc {
+			return less(us[j], us[i])
+		}
+		return less(us[i], us[j])
+	})
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetUsersSorted(t *testing.T) {
+	resetUsers()
+	for _, u := range []User{
+		{FirstName: "john", LastName: "Smith"},
+		{FirstName: "Fadi", LastName: "kaba"},
+		{FirstName: "Jane", LastName: "Doe"},
+	} {
+		_, _ = AddUser(u)
+	}
+
+	tests := []struct {
+		name string
+		by   SortField
+		desc bool
+		want []int
+	}{
+		{"id", SortByID, false, []int{1, 2, 3}},
+		{"id-desc", SortByID, true, []int{3, 2, 1}},
+		{"first", SortByFirstName, false, []int{2, 3, 1}},
+		{"first-desc", SortByFirstName, true, []int{1, 3, 2}},
+		{"last", SortByLastName, false, []int{3, 2, 1}},
+		{"last-desc", SortByLastName, true, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		var got []int
+		for _, u := range GetUsersSorted(tt.by, tt.desc) {
+			got = append(got, u.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected IDs %v but got %v", tt.name, tt.want, got)
+		}
+	}
+
+	sorted := GetUsersSorted(SortByFirstName, false)
+	sorted[0].FirstName = "Changed"
+	if u, _ := GetUser(sorted[0].ID); u.FirstName == "Changed" {
+		t.Error("Expected the sorted users to be copies")
+	}
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQLStore is a UserStore backed by a SQLite database through database/sql.
+// The schema and queries are written for SQLite only. The caller opens the
+// *sql.DB with a SQLite driver and closes it when done.
+type SQLStore struct {
+	db *sql.DB
+}
+
+var _ UserStore = (*SQLStore)(nil)
+
+const createUsersTable = `CREATE TABLE IF NOT EXISTS users (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	first_name TEXT NOT NULL,
+	last_name  TEXT NOT NULL
+)`
+
+// NewSQLStore returns a store using db, creating the users table if absent
+func NewSQLStore(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	if _, err := db.ExecContext(ctx, createUsersTable); err != nil {
+		return nil, fmt.Errorf("create users table: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Ping checks that the database can still be reached
+func (s *SQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLStore) AddUser(ctx context.Context, u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `INSERT INTO users (first_name, last_name) VALUES (?, ?)`, u.FirstName, u.LastName)
+	if err != nil {
+		return User{}, fmt.Errorf("add user: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return User{}, fmt.Errorf("add user: %w", err)
+	}
+	u.ID = int(id)
+	return u, nil
+}
+
+func (s *SQLStore) GetUser(ctx context.Context, id int) (User, error) {
+	u := User{ID: id}
+	err := s.db.QueryRowContext(ctx, `SELECT first_name, last_name FROM users WHERE id = ?`, id).Scan(&u.FirstName, &u.LastName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("get user %d: %w", id, err)
+	}
+	return u, nil
+}
+
+func (s *SQLStore) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, first_name, last_name FROM users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.FirstName, &u.LastName); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	return users, nil
+}
+
+func (s *SQLStore) UpdateUser(ctx context.Context, id int, u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET first_name = ?, last_name = ? WHERE id = ?`, u.FirstName, u.LastName, id)
+	if err != nil {
+		return User{}, fmt.Errorf("update user %d: %w", id, err)
+	}
+	// SQLite counts the rows an UPDATE matched, so an update that writes the
+	// values a user already has still affects one row
+	if err := requireRow(res, id); err != nil {
+		return User{}, err
+	}
+	u.ID = id
+	return u, nil
+}
+
+func (s *SQLStore) DeleteUser(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete user %d: %w", id, err)
+	}
+	return requireRow(res, id)
+}
+
+// requireRow returns ErrUserNotFound when res changed no rows
+func requireRow(res sql.Result, id int) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("user %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	return nil
+}
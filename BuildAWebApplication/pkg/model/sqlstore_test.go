@@ -0,0 +1,105 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestSQLStore(t *testing.T) *SQLStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	s, err := NewSQLStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	return s
+}
+
+func TestSQLStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLStore(t)
+
+	fadi, err := s.AddUser(ctx, User{FirstName: "Fadi", LastName: "Kaba"})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	john, _ := s.AddUser(ctx, User{FirstName: "John", LastName: "Smith"})
+	if fadi.ID == 0 || john.ID == fadi.ID {
+		t.Errorf("Expected distinct generated IDs, got %d and %d", fadi.ID, john.ID)
+	}
+
+	got, err := s.GetUser(ctx, fadi.ID)
+	if err != nil || got != fadi {
+		t.Errorf("Expected %+v but got %+v (%v)", fadi, got, err)
+	}
+
+	if _, err := s.UpdateUser(ctx, fadi.ID, fadi); err != nil {
+		t.Errorf("Expected an update that changes nothing to succeed, got %v", err)
+	}
+
+	updated, err := s.UpdateUser(ctx, john.ID, User{FirstName: "Johnny", LastName: "Smith"})
+	if err != nil || updated.ID != john.ID {
+		t.Errorf("Unexpected update result %+v (%v)", updated, err)
+	}
+
+	if err := s.DeleteUser(ctx, fadi.ID); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	list, err := s.ListUsers(ctx)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if want := []User{updated}; !reflect.DeepEqual(list, want) {
+		t.Errorf("Expected %v but got %v", want, list)
+	}
+
+	if _, err := s.AddUser(ctx, User{FirstName: "Jane"}); !errors.Is(err, &ErrValidation{}) {
+		t.Errorf("Expected a validation error but got %v", err)
+	}
+}
+
+func TestSQLStoreNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLStore(t)
+
+	if _, err := s.GetUser(ctx, 42); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUser: expected ErrUserNotFound but got %v", err)
+	}
+	if _, err := s.UpdateUser(ctx, 42, User{FirstName: "A", LastName: "B"}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("UpdateUser: expected ErrUserNotFound but got %v", err)
+	}
+	if err := s.DeleteUser(ctx, 42); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("DeleteUser: expected ErrUserNotFound but got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.ListUsers(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled context to stop the query, got %v", err)
+	}
+}
+
+func TestSQLStorePing(t *testing.T) {
+	s := newTestSQLStore(t)
+	if err := s.Ping(context.Background()); err != nil {
+		t.Errorf("Got an error when should not have: %v", err)
+	}
+
+	s.db.Close()
+	if err := s.Ping(context.Background()); err == nil {
+		t.Error("Expected an error pinging a closed database")
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import "context"
+
+// UserStore stores users. Implementations are safe for concurrent use.
+type UserStore interface {
+	AddUser(ctx context.Context, u User) (User, error)
+	GetUser(ctx context.Context, id int) (User, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	UpdateUser(ctx context.Context, id int, u User) (User, error)
+	DeleteUser(ctx context.Context, id int) error
+}
+
+// MemoryStore is the package's in-memory store exposed as a UserStore. Every
+// MemoryStore shares the same users.
+type MemoryStore struct{}
+
+var _ UserStore = MemoryStore{}
+
+func (MemoryStore) AddUser(_ context.Context, u User) (User, error) { return AddUser(u) }
+
+func (MemoryStore) GetUser(_ context.Context, id int) (User, error) { return GetUser(id) }
+
+func (MemoryStore) ListUsers(context.Context) ([]User, error) { return GetUsers(), nil }
+
+func (MemoryStore) UpdateUser(_ context.Context, id int, u User) (User, error) {
+	return UpdateUser(id, u)
+}
+
+func (MemoryStore) DeleteUser(_ context.Context, id int) error { return DeleteUser(id) }
+
+// AddUserIdempotent is AddUserIdempotent on the in-memory store
+func (MemoryStore) AddUserIdempotent(_ context.Context, key string, u User) (User, bool, error) {
+	return AddUserIdempotent(key, u)
+}
+
+// Ping always succeeds; the in-memory store can't be unreachable
+func (MemoryStore) Ping(context.Context) error { return nil }
+
+// CountUsers is CountUsers on the in-memory store
+func (MemoryStore) CountUsers(context.Context) (int, error) { return CountUsers(), nil }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// uniqueNames makes the in-memory store reject a user whose name matches one
+// already stored. It is off by default.
+var uniqueNames bool
+
+// SetUniqueNames turns the (first, last) name uniqueness check on or off for
+// the in-memory store. While on, AddUser and UpdateUser return ErrDuplicate
+// for a name that matches another user's, ignoring case and surrounding
+// whitespace. Users already stored are not checked when it is turned on.
+func SetUniqueNames(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	uniqueNames = on
+}
+
+// sameName reports whether a and b have the same name for the uniqueness check
+func sameName(a, b User) bool {
+	return strings.EqualFold(strings.TrimSpace(a.FirstName), strings.TrimSpace(b.FirstName)) &&
+		strings.EqualFold(strings.TrimSpace(a.LastName), strings.TrimSpace(b.LastName))
+}
+
+// checkUniqueName returns ErrDuplicate if uniqueness is on and a user other
+// than the one with ID except already has u's name. mu must be held.
+func checkUniqueName(u User, except int) error {
+	if !uniqueNames {
+		return nil
+	}
+	for _, existing := range users {
+		if existing.ID != except && sameName(*existing, u) {
+			return fmt.Errorf("%s %s is already user %d: %w", u.FirstName, u.LastName, existing.ID, ErrDuplicate)
+		}
+	}
+	return nil
+}
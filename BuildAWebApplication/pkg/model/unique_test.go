@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUniqueNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		unique  bool
+		second  User
+		wantDup bool
+	}{
+		{"off-same-name", false, User{FirstName: "Fadi", LastName: "Kaba"}, false},
+		{"on-same-name", true, User{FirstName: "Fadi", LastName: "Kaba"}, true},
+		{"on-case-and-space", true, User{FirstName: " fadi", LastName: "KABA "}, true},
+		{"on-different-name", true, User{FirstName: "Fadi", LastName: "Smith"}, false},
+	}
+
+	for _, tt := range tests {
+		resetUsers()
+		SetUniqueNames(tt.unique)
+
+		if _, err := AddUser(User{FirstName: "Fadi", LastName: "Kaba"}); err != nil {
+			t.Fatalf("%s: got an error when should not have: %v", tt.name, err)
+		}
+		_, err := AddUser(tt.second)
+		if got := errors.Is(err, ErrDuplicate); got != tt.wantDup {
+			t.Errorf("%s: expected a duplicate error to be %v, got %v", tt.name, tt.wantDup, err)
+		}
+	}
+}
+
+func TestUniqueNamesUpdate(t *testing.T) {
+	resetUsers()
+	SetUniqueNames(true)
+
+	fadi, _ := AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+	john, _ := AddUser(User{FirstName: "John", LastName: "Smith"})
+
+	if _, err := UpdateUser(john.ID, User{FirstName: "FADI", LastName: "kaba"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected renaming to a taken name to fail with ErrDuplicate, got %v", err)
+	}
+	if _, err := UpdateUser(fadi.ID, User{FirstName: "fadi", LastName: "Kaba"}); err != nil {
+		t.Errorf("Expected a user to keep their own name, got %v", err)
+	}
+}
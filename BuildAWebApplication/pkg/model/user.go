@@ -0,0 +1,166 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type User struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+var (
+	mu    sync.Mutex
+	users []*User
+	ids   IDGenerator = &SequentialIDs{}
+
+	// idempotencyKeys maps client-supplied keys to the ID they created
+	idempotencyKeys = map[string]int{}
+)
+
+// GetUsers returns a point-in-time copy of the stored users. Later changes to
+// the store are not reflected in the returned slice, and changing the slice
+// does not affect the store.
+func GetUsers() []User {
+	mu.Lock()
+	defer mu.Unlock()
+
+	snapshot := make([]User, len(users))
+	for i, u := range users {
+		snapshot[i] = *u
+	}
+	return snapshot
+}
+
+// CountUsers returns the number of stored users
+func CountUsers() int {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(users)
+}
+
+func AddUser(u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return addUser(u)
+}
+
+// addUser stores u under the next ID. It fails with ErrDuplicate if the ID
+// generator returns an ID that is already in use, or if the name is taken
+// while SetUniqueNames is on. mu must be held.
+func addUser(u User) (User, error) {
+	if err := checkUniqueName(u, 0); err != nil {
+		return User{}, err
+	}
+	u.ID = ids.Next()
+	if indexOf(u.ID) >= 0 {
+		return User{}, fmt.Errorf("user %d: %w", u.ID, ErrDuplicate)
+	}
+	users = append(users, &u)
+	logger.Debug("user added", "id", u.ID)
+	notifyAdded(u)
+	return u, nil
+}
+
+// AddUsers adds each user in turn. A user that fails validation does not stop
+// the batch: errs is parallel to us and holds nil for every user that was added.
+// If ctx is cancelled part way, the remaining users are not added, their errs
+// entries hold ctx.Err(), and ctx.Err() is returned along with the users added
+// so far.
+func AddUsers(ctx context.Context, us []User) ([]User, []error, error) {
+	added := make([]User, 0, len(us))
+	errs := make([]error, len(us))
+	for i, u := range us {
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(us); j++ {
+				errs[j] = err
+			}
+			return added, errs, err
+		}
+
+		nu, err := AddUser(u)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		added = append(added, nu)
+	}
+	return added, errs, nil
+}
+
+// GetUser returns the user with the given ID
+func GetUser(id int) (User, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOf(id)
+	if i < 0 {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	return *users[i], nil
+}
+
+// UpdateUser replaces the stored user with the given ID, keeping the ID
+func UpdateUser(id int, u User) (User, error) {
+	if err := validateUser(u); err != nil {
+		return User{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOf(id)
+	if i < 0 {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	if err := checkUniqueName(u, id); err != nil {
+		return User{}, err
+	}
+	u.ID = id
+	*users[i] = u
+	logger.Debug("user updated", "id", id)
+	return u, nil
+}
+
+// DeleteUser removes the user with the given ID
+func DeleteUser(id int) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOf(id)
+	if i < 0 {
+		return fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	users = append(users[:i], users[i+1:]...)
+	logger.Debug("user deleted", "id", id)
+	return nil
+}
+
+// indexOf returns the position of the user with the given ID, or -1.
+// mu must be held.
+func indexOf(id int) int {
+	for i, u := range users {
+		if u.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateUser(u User) error {
+	if strings.TrimSpace(u.FirstName) == "" {
+		return &ErrValidation{Field: "firstName", Reason: "is required"}
+	}
+	if strings.TrimSpace(u.LastName) == "" {
+		return &ErrValidation{Field: "lastName", Reason: "is required"}
+	}
+	return nil
+}
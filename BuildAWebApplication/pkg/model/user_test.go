@@ -0,0 +1,181 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func resetUsers() {
+	users = nil
+	ids = &SequentialIDs{}
+	idempotencyKeys = map[string]int{}
+	notifiers = nil
+	uniqueNames = false
+}
+
+func TestAddUsers(t *testing.T) {
+	resetUsers()
+
+	in := []User{
+		{FirstName: "Fadi", LastName: "Kaba"},
+		{FirstName: "", LastName: "NoFirst"},
+		{FirstName: "John", LastName: "Smith"},
+		{FirstName: "NoLast", LastName: " "},
+		{FirstName: "Jane", LastName: "Doe"},
+	}
+
+	added, errs, err := AddUsers(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	if len(errs) != len(in) {
+		t.Fatalf("Expected %d errors but got %d", len(in), len(errs))
+	}
+	for i, wantErr := range []bool{false, true, false, true, false} {
+		if wantErr && errs[i] == nil {
+			t.Errorf("Expected an error for user %d but didn't get one", i)
+		}
+		if !wantErr && errs[i] != nil {
+			t.Errorf("Got an error for user %d when should not have: %v", i, errs[i])
+		}
+	}
+
+	if len(added) != 3 {
+		t.Fatalf("Expected 3 added users but got %d", len(added))
+	}
+	for i, u := range added {
+		if u.ID != i+1 {
+			t.Errorf("Expected ID %d but got %d", i+1, u.ID)
+		}
+	}
+	if len(GetUsers()) != 3 {
+		t.Errorf("Expected 3 stored users but got %d", len(GetUsers()))
+	}
+}
+
+// countdownCtx reports itself cancelled once Err has been called n times,
+// so a test can cancel a batch at an exact item
+type countdownCtx struct {
+	context.Context
+	n int
+}
+
+func (c *countdownCtx) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestAddUsersCancelled(t *testing.T) {
+	resetUsers()
+
+	in := make([]User, 5)
+	for i := range in {
+		in[i] = User{FirstName: "Fadi", LastName: "Kaba"}
+	}
+
+	added, errs, err := AddUsers(&countdownCtx{Context: context.Background(), n: 2}, in)
+
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled but got %v", err)
+	}
+	if len(added) != 2 || CountUsers() != 2 {
+		t.Errorf("Expected only the 2 users before cancellation to be added, got %d (%d stored)", len(added), CountUsers())
+	}
+	for i, want := range []error{nil, nil, context.Canceled, context.Canceled, context.Canceled} {
+		if errs[i] != want {
+			t.Errorf("Expected errs[%d] to be %v but got %v", i, want, errs[i])
+		}
+	}
+}
+
+func TestUpdateAndDeleteUser(t *testing.T) {
+	resetUsers()
+	u, _ := AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+
+	updated, err := UpdateUser(u.ID, User{FirstName: "John", LastName: "Smith"})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if updated.ID != u.ID || updated.FirstName != "John" {
+		t.Errorf("Unexpected updated user %+v", updated)
+	}
+
+	got, err := GetUser(u.ID)
+	if err != nil || got.LastName != "Smith" {
+		t.Errorf("Expected the stored user to be updated, got %+v (%v)", got, err)
+	}
+
+	if err := DeleteUser(u.ID); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if _, err := GetUser(u.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound after delete but got %v", err)
+	}
+	if err := DeleteUser(u.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound deleting twice but got %v", err)
+	}
+	if _, err := UpdateUser(42, User{FirstName: "A", LastName: "B"}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound updating an unknown user but got %v", err)
+	}
+}
+
+func TestGetUsersSnapshot(t *testing.T) {
+	resetUsers()
+	for i := 0; i < 10; i++ {
+		_, _ = AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_, _ = AddUser(User{FirstName: "John", LastName: "Smith"})
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		snapshot := GetUsers()
+		if len(snapshot) < 10 {
+			t.Fatalf("Expected at least 10 users but got %d", len(snapshot))
+		}
+		for j, u := range snapshot {
+			if u.ID != j+1 || u.FirstName == "" {
+				t.Fatalf("Got an inconsistent user %+v at position %d", u, j)
+			}
+		}
+	}
+	wg.Wait()
+
+	snapshot := GetUsers()
+	snapshot[0].FirstName = "Changed"
+	if u, _ := GetUser(snapshot[0].ID); u.FirstName == "Changed" {
+		t.Error("Expected changes to the snapshot not to affect the store")
+	}
+}
+
+func TestCountUsers(t *testing.T) {
+	resetUsers()
+	if got := CountUsers(); got != 0 {
+		t.Errorf("Expected 0 users but got %d", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, _ = AddUser(User{FirstName: "Fadi", LastName: "Kaba"})
+	}
+	_, _ = AddUser(User{FirstName: "", LastName: "Invalid"})
+	if got := CountUsers(); got != 3 {
+		t.Errorf("Expected 3 users but got %d", got)
+	}
+
+	_ = DeleteUser(1)
+	if got := CountUsers(); got != 2 {
+		t.Errorf("Expected 2 users after a delete but got %d", got)
+	}
+}
@@ -1,17 +1,266 @@
 package render
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"log"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/config"
 )
 
-// RenderTemplate renders template using the html
+// TemplatePath is the directory templates are loaded from
+var TemplatePath = "./templates"
+
+var app *config.AppConfig
 
-func RenderTemplate(w http.ResponseWriter, tmpl string) {
-	parsedTemplate, _ := template.ParseFiles("./templates/"+tmpl, "./templates/base.layout.tmpl.html")
-	err := parsedTemplate.Execute(w, nil)
+var functions = template.FuncMap{
+	"formatMoney": FormatMoney,
+	"humanDate":   HumanDate,
+	"csrfField":   CSRFField,
+}
+
+// FormatMoney formats an amount as dollars with two decimal places
+func FormatMoney(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// HumanDate formats t as e.g. "02 Jan 2006"
+func HumanDate(t time.Time) string {
+	return t.Format("02 Jan 2006")
+}
+
+// CSRFFieldName is the form field CSRFField writes the token to
+const CSRFFieldName = "csrf_token"
+
+// CSRFField returns the hidden form input carrying td's CSRFToken, for use in
+// templates as {{csrfField .}}. The token is escaped here because the pages
+// are text/templates, which don't escape their output.
+func CSRFField(td *TemplateData) htmltemplate.HTML {
+	var token string
+	if td != nil {
+		token = td.CSRFToken
+	}
+	return htmltemplate.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, CSRFFieldName, html.EscapeString(token)))
+}
+
+// templateFuncs returns the built-in functions merged with any from the config
+func templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, fn := range functions {
+		funcs[name] = fn
+	}
+	if app != nil {
+		for name, fn := range app.Functions {
+			funcs[name] = fn
+		}
+	}
+	return funcs
+}
+
+// NewTemplates sets the config for the render package
+func NewTemplates(a *config.AppConfig) {
+	app = a
+}
+
+// TemplateData holds data sent from handlers to templates
+type TemplateData struct {
+	StringMap map[string]string
+	Data      map[string]interface{}
+
+	// CSRFToken is the token forms must send back, written by csrfField
+	CSRFToken string
+}
+
+// ErrorTemplate is the page used for error responses, including when the
+// requested template is missing. It is given the StringMap keys "status",
+// "statusText" and "message".
+var ErrorTemplate = "error.page.tmpl.html"
+
+// ErrorData returns the TemplateData ErrorTemplate expects for status and msg
+func ErrorData(status int, msg string) *TemplateData {
+	return &TemplateData{StringMap: map[string]string{
+		"status":     strconv.Itoa(status),
+		"statusText": http.StatusText(status),
+		"message":    msg,
+	}}
+}
+
+// renderServerError writes a 500 using the error page from tc, falling back
+// to plain text when the error page is missing too
+func renderServerError(w http.ResponseWriter, tc map[string]*template.Template) {
+	status := http.StatusInternalServerError
+
+	buf := new(bytes.Buffer)
+	t, ok := tc[ErrorTemplate]
+	if ok {
+		ok = t.Execute(buf, ErrorData(status, "The page could not be displayed.")) == nil
+	}
+	if !ok {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// ErrTemplateNotFound is returned by RenderToString for an unknown template
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrCacheEmpty is returned by CacheReady before any templates are loaded
+var ErrCacheEmpty = errors.New("template cache is empty")
+
+// CacheReady reports whether the template cache set with NewTemplates holds
+// any templates
+func CacheReady() error {
+	if app == nil || len(app.TemplateCache) == 0 {
+		return ErrCacheEmpty
+	}
+	return nil
+}
+
+// templateCache returns the cached templates when UseCache is set, otherwise
+// it re-parses them from disk so edits show up without a restart
+func templateCache() (map[string]*template.Template, error) {
+	if app != nil && app.UseCache {
+		return app.TemplateCache, nil
+	}
+	return CreateTemplateCache()
+}
+
+// RenderToString renders tmpl with data and returns the output, for content
+// that isn't an HTTP response such as an email body. Templates are looked up
+// the same way as RenderTemplate.
+func RenderToString(tmpl string, data interface{}) (string, error) {
+	tc, err := templateCache()
+	if err != nil {
+		return "", err
+	}
+
+	t, ok := tc[tmpl]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", tmpl, ErrTemplateNotFound)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderTemplate renders template using the html
+// Templates come from the cache when UseCache is set, otherwise they are
+// re-parsed from disk on every call so edits show up without a restart.
+func RenderTemplate(w http.ResponseWriter, tmpl string, td *TemplateData) {
+	tc, err := templateCache()
 	if err != nil {
 		fmt.Println("error parsing template:", err)
+		return
+	}
+
+	t, ok := tc[tmpl]
+	if !ok {
+		log.Println("could not get template from cache:", tmpl)
+		renderServerError(w, tc)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, td); err != nil {
+		fmt.Println("error executing template:", err)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		fmt.Println("error writing template to browser:", err)
+	}
+}
+
+// renderChunk is how much RenderTemplateCtx writes between context checks
+const renderChunk = 32 << 10
+
+// RenderTemplateCtx renders tmpl like RenderTemplate, but gives up once ctx
+// is done. The page is rendered into a buffer first and then written out in
+// chunks, checking ctx before each one, so a client that disconnects partway
+// through a large page stops the copy promptly. A write that is already
+// blocked on the client is bounded by the server's WriteTimeout, not ctx.
+// It returns ctx.Err() when cancelled, and ErrTemplateNotFound, after
+// writing the error page, for an unknown template.
+func RenderTemplateCtx(ctx context.Context, w http.ResponseWriter, tmpl string, td *TemplateData) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tc, err := templateCache()
+	if err != nil {
+		return err
+	}
+
+	t, ok := tc[tmpl]
+	if !ok {
+		renderServerError(w, tc)
+		return fmt.Errorf("%s: %w", tmpl, ErrTemplateNotFound)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, td); err != nil {
+		return err
+	}
+
+	for buf.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := w.Write(buf.Next(renderChunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTemplateCache parses every page in TemplatePath together with the layouts
+func CreateTemplateCache() (map[string]*template.Template, error) {
+	myCache := map[string]*template.Template{}
+
+	pages, err := filepath.Glob(filepath.Join(TemplatePath, "*.page.tmpl.html"))
+	if err != nil {
+		return myCache, err
+	}
+
+	layouts, err := filepath.Glob(filepath.Join(TemplatePath, "*.layout.tmpl.html"))
+	if err != nil {
+		return myCache, err
 	}
+
+	for _, page := range pages {
+		name := filepath.Base(page)
+		ts, err := template.New(name).Funcs(templateFuncs()).ParseFiles(page)
+		if err != nil {
+			return myCache, err
+		}
+
+		if len(layouts) > 0 {
+			ts, err = ts.ParseFiles(layouts...)
+			if err != nil {
+				return myCache, err
+			}
+		}
+
+		myCache[name] = ts
+	}
+
+	return myCache, nil
 }
@@ -0,0 +1,242 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kabaf81/BuildAWebApplication/pkg/config"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderTemplateUseCache(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	writeTemplate(t, dir, "base.layout.tmpl.html", `{{define "base"}}{{block "content" .}}{{end}}{{end}}`)
+	writeTemplate(t, dir, "home.page.tmpl.html", `{{template "base" .}}{{define "content"}}before{{end}}`)
+
+	tc, err := CreateTemplateCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var testApp config.AppConfig
+	testApp.TemplateCache = tc
+	NewTemplates(&testApp)
+	defer NewTemplates(nil)
+
+	writeTemplate(t, dir, "home.page.tmpl.html", `{{template "base" .}}{{define "content"}}after{{end}}`)
+
+	tests := []struct {
+		useCache bool
+		expected string
+	}{
+		{true, "before"},
+		{false, "after"},
+	}
+
+	for _, tt := range tests {
+		testApp.UseCache = tt.useCache
+		rr := httptest.NewRecorder()
+		RenderTemplate(rr, "home.page.tmpl.html", &TemplateData{})
+
+		if got := strings.TrimSpace(rr.Body.String()); got != tt.expected {
+			t.Errorf("UseCache=%v: expected %q but got %q", tt.useCache, tt.expected, got)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	testApp := config.AppConfig{
+		Functions: map[string]interface{}{"shout": strings.ToUpper},
+	}
+	NewTemplates(&testApp)
+	defer NewTemplates(nil)
+
+	writeTemplate(t, dir, "menu.page.tmpl.html",
+		`{{formatMoney (index .Data "price")}}|{{humanDate (index .Data "date")}}|{{shout "tea"}}`)
+
+	rr := httptest.NewRecorder()
+	RenderTemplate(rr, "menu.page.tmpl.html", &TemplateData{Data: map[string]interface{}{
+		"price": 1.6,
+		"date":  time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+	}})
+
+	want := "$1.60|05 Mar 2024|TEA"
+	if got := rr.Body.String(); got != want {
+		t.Errorf("Expected %q but got %q", want, got)
+	}
+}
+
+func TestRenderTemplateMissing(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	writeTemplate(t, dir, "base.layout.tmpl.html", `{{define "base"}}{{block "content" .}}{{end}}{{end}}`)
+	writeTemplate(t, dir, ErrorTemplate, `{{template "base" .}}{{define "content"}}error: {{index .StringMap "message"}}{{end}}`)
+
+	tc, err := CreateTemplateCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testApp := config.AppConfig{UseCache: true, TemplateCache: tc}
+	NewTemplates(&testApp)
+	defer NewTemplates(nil)
+
+	rr := httptest.NewRecorder()
+	RenderTemplate(rr, "bogus.page.tmpl.html", &TemplateData{})
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d but got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != "error: The page could not be displayed." {
+		t.Errorf("Expected the error page but got %q", got)
+	}
+
+	delete(tc, ErrorTemplate)
+	rr = httptest.NewRecorder()
+	RenderTemplate(rr, "bogus.page.tmpl.html", &TemplateData{})
+	if rr.Code != http.StatusInternalServerError || !strings.Contains(rr.Body.String(), "Internal Server Error") {
+		t.Errorf("Expected a plain 500 without an error page, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRenderToString(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	writeTemplate(t, dir, "base.layout.tmpl.html", `{{define "base"}}{{block "content" .}}{{end}}{{end}}`)
+	writeTemplate(t, dir, "welcome.page.tmpl.html", `{{template "base" .}}{{define "content"}}Hello {{index .StringMap "name"}}, you owe {{formatMoney 12.5}}{{end}}`)
+
+	got, err := RenderToString("welcome.page.tmpl.html", &TemplateData{StringMap: map[string]string{"name": "Fadi"}})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if want := "Hello Fadi, you owe $12.50"; strings.TrimSpace(got) != want {
+		t.Errorf("Expected %q but got %q", want, got)
+	}
+
+	if _, err := RenderToString("bogus.page.tmpl.html", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound but got %v", err)
+	}
+}
+
+func TestErrorTemplateConfigurable(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	saved := ErrorTemplate
+	ErrorTemplate = "oops.page.tmpl.html"
+	defer func() { ErrorTemplate = saved }()
+
+	writeTemplate(t, dir, ErrorTemplate, `oops {{index .StringMap "status"}} {{index .StringMap "statusText"}}: {{index .StringMap "message"}}`)
+
+	rr := httptest.NewRecorder()
+	RenderTemplate(rr, "bogus.page.tmpl.html", &TemplateData{})
+
+	if want := "oops 500 Internal Server Error: The page could not be displayed."; strings.TrimSpace(rr.Body.String()) != want {
+		t.Errorf("Expected %q but got %q", want, rr.Body.String())
+	}
+}
+
+func TestCSRFField(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	writeTemplate(t, dir, "form.page.tmpl.html", `<form method="post">{{csrfField .}}<button>Save</button></form>`)
+
+	tests := []struct {
+		token    string
+		expected string
+	}{
+		{"abc123", `<input type="hidden" name="csrf_token" value="abc123">`},
+		{`a"><script>`, `<input type="hidden" name="csrf_token" value="a&#34;&gt;&lt;script&gt;">`},
+	}
+
+	for _, tt := range tests {
+		got, err := RenderToString("form.page.tmpl.html", &TemplateData{CSRFToken: tt.token})
+		if err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+		if want := `<form method="post">` + tt.expected + `<button>Save</button></form>`; got != want {
+			t.Errorf("Expected %q but got %q", want, got)
+		}
+	}
+}
+
+// cancelWriter cancels its context after the first write
+type cancelWriter struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (w cancelWriter) Write(b []byte) (int, error) {
+	defer w.cancel()
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestRenderTemplateCtx(t *testing.T) {
+	dir := t.TempDir()
+	TemplatePath = dir
+	defer func() { TemplatePath = "./templates" }()
+
+	writeTemplate(t, dir, "big.page.tmpl.html", `{{range .Data.rows}}{{.}}{{end}}`)
+	rows := make([]string, 4*renderChunk/8)
+	for i := range rows {
+		rows[i] = "row.....\n"
+	}
+	td := &TemplateData{Data: map[string]interface{}{"rows": rows}}
+
+	rr := httptest.NewRecorder()
+	if err := RenderTemplateCtx(context.Background(), rr, "big.page.tmpl.html", td); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if want := len(rows) * len(rows[0]); rr.Body.Len() != want {
+		t.Errorf("Expected %d bytes but got %d", want, rr.Body.Len())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rr = httptest.NewRecorder()
+	if err := RenderTemplateCtx(ctx, rr, "big.page.tmpl.html", td); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected nothing written for a cancelled context, got %d bytes", rr.Body.Len())
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	cw := cancelWriter{ResponseRecorder: httptest.NewRecorder(), cancel: cancel}
+	if err := RenderTemplateCtx(ctx, cw, "big.page.tmpl.html", td); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled mid-copy but got %v", err)
+	}
+	if cw.Body.Len() != renderChunk {
+		t.Errorf("Expected the copy to stop after one chunk of %d bytes, got %d", renderChunk, cw.Body.Len())
+	}
+
+	if err := RenderTemplateCtx(context.Background(), httptest.NewRecorder(), "bogus.page.tmpl.html", td); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound but got %v", err)
+	}
+}
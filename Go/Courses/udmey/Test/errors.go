@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDivideByZero is the reason a division by zero fails; match it with errors.Is
+var ErrDivideByZero = errors.New("division by zero")
+
+// DivideError records the operands of a division that failed and why
+type DivideError struct {
+	Dividend float32
+	Divisor  float32
+	Err      error
+}
+
+func (e *DivideError) Error() string {
+	return fmt.Sprintf("can't divide %v by %v: %v", e.Dividend, e.Divisor, e.Err)
+}
+
+func (e *DivideError) Unwrap() error {
+	return e.Err
+}
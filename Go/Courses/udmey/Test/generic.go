@@ -0,0 +1,12 @@
+package main
+
+import "golang.org/x/exp/constraints"
+
+// Divide returns x/y with T's own semantics, so integer division truncates.
+// A zero divisor returns ErrDivideByZero instead of panicking or giving Inf.
+func Divide[T constraints.Integer | constraints.Float](x, y T) (T, error) {
+	if y == 0 {
+		return 0, ErrDivideByZero
+	}
+	return x / y, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenericDivideInt(t *testing.T) {
+	tests := []struct {
+		x, y, want int
+	}{
+		{10, 2, 5},
+		{7, 2, 3},
+		{-7, 2, -3},
+	}
+
+	for _, tt := range tests {
+		got, err := Divide(tt.x, tt.y)
+		if err != nil {
+			t.Errorf("Divide(%d, %d): got an error when should not have: %v", tt.x, tt.y, err)
+		}
+		if got != tt.want {
+			t.Errorf("Divide(%d, %d): expected %d but got %d", tt.x, tt.y, tt.want, got)
+		}
+	}
+
+	if _, err := Divide(7, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("Expected ErrDivideByZero but got %v", err)
+	}
+}
+
+func TestGenericDivideFloat(t *testing.T) {
+	got, err := Divide(7.0, 2.0)
+	if err != nil {
+		t.Errorf("Got an error when should not have: %v", err)
+	}
+	if got != 3.5 {
+		t.Errorf("Expected 3.5 but got %v", got)
+	}
+
+	if _, err := Divide(7.0, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("Expected ErrDivideByZero but got %v", err)
+	}
+}
@@ -1,3 +1,5 @@
 module divide
 
 go 1.19
+
+require golang.org/x/exp v0.0.0-20220909182711-5c715a9e8561
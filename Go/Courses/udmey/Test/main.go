@@ -1,7 +1,6 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"log"
 )
@@ -23,7 +22,7 @@ func divide(x, y float32) (float32, error) {
 	var result float32
 
 	if y == 0 {
-		return result, errors.New("Can't divide by 0")
+		return result, &DivideError{Dividend: x, Divisor: y, Err: ErrDivideByZero}
 	}
 
 	result = x / y
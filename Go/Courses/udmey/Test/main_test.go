@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestDivide(t *testing.T) {
 	_, err := divide(10.00, 0)
@@ -8,3 +12,19 @@ func TestDivide(t *testing.T) {
 		t.Error("Got an error when should not have")
 	}
 }
+
+func TestDivideByZeroError(t *testing.T) {
+	_, err := divide(10.5, 0)
+
+	if !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("Expected errors.Is(err, ErrDivideByZero) but got %v", err)
+	}
+
+	var derr *DivideError
+	if !errors.As(err, &derr) || derr.Dividend != 10.5 || derr.Divisor != 0 {
+		t.Errorf("Expected a DivideError carrying the operands but got %#v", err)
+	}
+	if !strings.Contains(err.Error(), "10.5") || !strings.Contains(err.Error(), "by 0") {
+		t.Errorf("Expected the message to include the operands, got %q", err.Error())
+	}
+}
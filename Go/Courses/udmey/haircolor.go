@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HairColor is one of the known hair colors, in its canonical spelling
+type HairColor string
+
+const (
+	Black  HairColor = "Black"
+	Brown  HairColor = "Brown"
+	Blonde HairColor = "Blonde"
+	Red    HairColor = "Red"
+	Grey   HairColor = "Grey"
+	White  HairColor = "White"
+)
+
+// hairColors maps every accepted spelling, lower-cased, to its canonical form
+var hairColors = map[string]HairColor{
+	"black":  Black,
+	"brown":  Brown,
+	"blonde": Blonde,
+	"blond":  Blonde,
+	"red":    Red,
+	"grey":   Grey,
+	"gray":   Grey,
+	"white":  White,
+}
+
+// ParseHairColor returns the canonical HairColor for s, ignoring case and
+// surrounding whitespace
+func ParseHairColor(s string) (HairColor, error) {
+	c, ok := hairColors[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return "", fmt.Errorf("unknown hair color %q", s)
+	}
+	return c, nil
+}
+
+// UnmarshalJSON accepts any spelling ParseHairColor does. null leaves c
+// unset, matching what MarshalJSON writes for an unset color.
+func (c *HairColor) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("hair color must be a string: %w", err)
+	}
+	parsed, err := ParseHairColor(s)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// MarshalJSON writes the canonical spelling, or null when c is unset, and
+// rejects unknown colors
+func (c HairColor) MarshalJSON() ([]byte, error) {
+	if c == "" {
+		return []byte("null"), nil
+	}
+	parsed, err := ParseHairColor(string(c))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(parsed))
+}
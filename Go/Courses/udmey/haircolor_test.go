@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHairColorUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    HairColor
+		wantErr bool
+	}{
+		{`"Black"`, Black, false},
+		{`"bLoNdE"`, Blonde, false},
+		{`" gray "`, Grey, false},
+		{`"blond"`, Blonde, false},
+		{`null`, "", false},
+		{`"Purple"`, "", true},
+		{`""`, "", true},
+		{`42`, "", true},
+	}
+
+	for _, tt := range tests {
+		var p person
+		err := json.Unmarshal([]byte(`{"HairColor":`+tt.json+`}`), &p)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error but got %q", tt.json, p.HairColor)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: got an error when should not have: %v", tt.json, err)
+			continue
+		}
+		if p.HairColor != tt.want {
+			t.Errorf("%s: expected %q but got %q", tt.json, tt.want, p.HairColor)
+		}
+	}
+}
+
+func TestHairColorMarshal(t *testing.T) {
+	b, err := json.Marshal(person{HairColor: "GRAY"})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if !strings.Contains(string(b), `"HairColor":"Grey"`) {
+		t.Errorf("Expected the canonical color in %s", b)
+	}
+
+	var p person
+	if err := json.Unmarshal([]byte(`{"FirstName":"Ann"}`), &p); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	b, err = json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Expected a person without a hair color to marshal, got %v", err)
+	}
+	if !strings.Contains(string(b), `"HairColor":null`) {
+		t.Errorf("Expected a null hair color in %s", b)
+	}
+	var again person
+	if err := json.Unmarshal(b, &again); err != nil || again.HairColor != "" {
+		t.Errorf("Expected %s to decode back to an unset color, got %q, %v", b, again.HairColor, err)
+	}
+
+	if _, err := json.Marshal(person{HairColor: "Purple"}); err == nil || !strings.Contains(err.Error(), "Purple") {
+		t.Errorf("Expected an error naming the unknown color, got %v", err)
+	}
+}
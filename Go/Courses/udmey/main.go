@@ -7,10 +7,10 @@ import (
 )
 
 type person struct {
-	FirstName  string `json:"firstName"`
-	SecondName string `json:"secondName"`
-	HairColor  string `json:"HairColor"`
-	HasDog     bool   `json:"HasDog"`
+	FirstName  string    `json:"firstName"`
+	SecondName string    `json:"secondName"`
+	HairColor  HairColor `json:"HairColor"`
+	HasDog     bool      `json:"HasDog"`
 }
 
 func main() {
@@ -25,7 +25,7 @@ func main() {
 		{
 			"firstName":"FName2",
 			"secondName":"SName2",
-			"HairColor":"Blue",
+			"HairColor":"brown",
 			"HasDog":false
 		}
 	]`
@@ -45,7 +45,7 @@ func main() {
 	var m1 person
 	m1.FirstName = "M1FName"
 	m1.SecondName = "M1SName"
-	m1.HairColor = "M1Black"
+	m1.HairColor = Black
 	m1.HasDog = true
 
 	mySlice = append(mySlice, m1)
@@ -53,7 +53,7 @@ func main() {
 	var m2 person
 	m2.FirstName = "M2FName"
 	m2.SecondName = "M2SName"
-	m2.HairColor = "M2Black"
+	m2.HairColor = Red
 	m2.HasDog = false
 
 	mySlice = append(mySlice, m2)
@@ -0,0 +1,23 @@
+package main
+
+// CountWithDogs returns how many of people have a dog
+func CountWithDogs(people []person) int {
+	count := 0
+	for _, p := range people {
+		if p.HasDog {
+			count++
+		}
+	}
+	return count
+}
+
+// GroupByHairColor groups people by hair color, keeping their order within
+// each group. The result is never nil, so it can be ranged over or indexed
+// even for no people.
+func GroupByHairColor(people []person) map[string][]person {
+	groups := map[string][]person{}
+	for _, p := range people {
+		groups[string(p.HairColor)] = append(groups[string(p.HairColor)], p)
+	}
+	return groups
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountWithDogs(t *testing.T) {
+	tests := []struct {
+		name   string
+		people []person
+		want   int
+	}{
+		{"nil", nil, 0},
+		{"none", []person{{FirstName: "A"}, {FirstName: "B"}}, 0},
+		{"some", []person{{FirstName: "A", HasDog: true}, {FirstName: "B"}, {FirstName: "C", HasDog: true}}, 2},
+		{"all", []person{{FirstName: "A", HasDog: true}}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := CountWithDogs(tt.people); got != tt.want {
+			t.Errorf("%s: expected %d but got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGroupByHairColor(t *testing.T) {
+	a := person{FirstName: "A", HairColor: Black}
+	b := person{FirstName: "B", HairColor: Red}
+	c := person{FirstName: "C", HairColor: Black}
+
+	tests := []struct {
+		name   string
+		people []person
+		want   map[string][]person
+	}{
+		{"nil", nil, map[string][]person{}},
+		{"one-color", []person{a, c}, map[string][]person{"Black": {a, c}}},
+		{"mixed", []person{a, b, c}, map[string][]person{"Black": {a, c}, "Red": {b}}},
+	}
+
+	for _, tt := range tests {
+		got := GroupByHairColor(tt.people)
+		if got == nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v but got %v", tt.name, tt.want, got)
+		}
+	}
+}
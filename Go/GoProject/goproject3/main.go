@@ -13,10 +13,10 @@ func main() {
 }
 
 func addValue(ctx context.Context) context.Context {
-	return context.WithValue(ctx, "key", "test-value1")
+	return WithUserValue(ctx, "test-value1")
 }
 
 func readValue(ctx context.Context) {
-	val := ctx.Value("key")
+	val, _ := UserValue(ctx)
 	fmt.Println(val)
 }
@@ -0,0 +1,18 @@
+package main
+
+import "context"
+
+// userValueKey is unexported, so no other package can build a key equal to
+// it, unlike a bare string such as "key".
+type userValueKey struct{}
+
+// WithUserValue returns a copy of ctx carrying v
+func WithUserValue(ctx context.Context, v string) context.Context {
+	return context.WithValue(ctx, userValueKey{}, v)
+}
+
+// UserValue returns the value stored by WithUserValue, and false if there is none
+func UserValue(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(userValueKey{}).(string)
+	return v, ok
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// otherPackageKey stands in for a string key set by unrelated code
+const otherPackageKey = "key"
+
+func TestUserValue(t *testing.T) {
+	if _, ok := UserValue(context.Background()); ok {
+		t.Error("Expected no value on an empty context")
+	}
+
+	ctx := WithUserValue(context.Background(), "test-value1")
+	// a bare string key, as the old example used
+	ctx = context.WithValue(ctx, otherPackageKey, "other-value")
+
+	if got, ok := UserValue(ctx); !ok || got != "test-value1" {
+		t.Errorf("Expected test-value1 but got %q (%v)", got, ok)
+	}
+	if got := ctx.Value(otherPackageKey); got != "other-value" {
+		t.Errorf("Expected the string key to keep its own value, got %v", got)
+	}
+}
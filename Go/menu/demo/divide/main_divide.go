@@ -1,10 +1,9 @@
 package mainv1
 
 import (
-	"bufio"
+	"demo/input"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 )
 
@@ -15,12 +14,8 @@ func mainv1() {
 		fmt.Println("2) Add item")
 		fmt.Println("3 Exit")
 
-		in := bufio.NewReader(os.Stdin)
-		s, _ := in.ReadString('\n')
-		s = strings.TrimSpace(s)
-		s = strings.ToUpper(s)
-
-		i, _ := strconv.Atoi(s)
+		in := input.NewLineReader(os.Stdin)
+		i, _ := in.ReadInt()
 		if i < 4 {
 			i--
 			displayMenu(i)
@@ -0,0 +1,41 @@
+// Package input reads line-oriented answers from the user, such as the
+// choices typed at the menu prompts.
+package input
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LineReader reads whitespace-trimmed lines from an io.Reader
+type LineReader struct {
+	r *bufio.Reader
+}
+
+// NewLineReader returns a LineReader reading from r
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{r: bufio.NewReader(r)}
+}
+
+// ReadLine returns the next line with surrounding whitespace removed. A last
+// line without a trailing newline is returned normally; io.EOF is only
+// returned once there is nothing left to read.
+func (lr *LineReader) ReadLine() (string, error) {
+	line, err := lr.r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// ReadInt reads the next line and parses it as an int. A line that isn't a
+// number returns a *strconv.NumError.
+func (lr *LineReader) ReadInt() (int, error) {
+	line, err := lr.ReadLine()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(line)
+}
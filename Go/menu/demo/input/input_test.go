@@ -0,0 +1,48 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	lr := NewLineReader(strings.NewReader("  Coffee \n\nTea"))
+
+	for _, want := range []string{"Coffee", "", "Tea"} {
+		got, err := lr.ReadLine()
+		if err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected %q but got %q", want, got)
+		}
+	}
+
+	if _, err := lr.ReadLine(); err != io.EOF {
+		t.Errorf("Expected io.EOF but got %v", err)
+	}
+}
+
+func TestReadInt(t *testing.T) {
+	lr := NewLineReader(strings.NewReader(" 42\nabc\n-3"))
+
+	if n, err := lr.ReadInt(); err != nil || n != 42 {
+		t.Errorf("Expected 42 but got %d (%v)", n, err)
+	}
+
+	var numErr *strconv.NumError
+	if _, err := lr.ReadInt(); !errors.As(err, &numErr) {
+		t.Errorf("Expected a *strconv.NumError for non-numeric input but got %v", err)
+	}
+
+	if n, err := lr.ReadInt(); err != nil || n != -3 {
+		t.Errorf("Expected -3 but got %d (%v)", n, err)
+	}
+
+	if _, err := lr.ReadInt(); err != io.EOF {
+		t.Errorf("Expected io.EOF but got %v", err)
+	}
+}
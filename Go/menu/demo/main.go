@@ -1,30 +1,41 @@
 package main
 
 import (
-	"bufio"
+	"demo/input"
 	"demo/menu"
+	"flag"
 	"fmt"
 	"os"
-	"strings"
 )
 
-var in = bufio.NewReader(os.Stdin)
+var in = input.NewLineReader(os.Stdin)
 
 func main() {
+	menuFile := flag.String("menu", "", "load the menu from this JSON or CSV file at startup")
+	flag.Parse()
+
+	if *menuFile != "" {
+		if err := menu.LoadFile(*menuFile); err != nil {
+			fmt.Printf("Could not load the menu from %s (%v), starting with an empty menu\n", *menuFile, err)
+		}
+	}
+
 loop:
 	for {
 		fmt.Println("1) Print Menu")
 		fmt.Println("2) Add item")
 		fmt.Println("3 Exit")
 
-		choice, _ := in.ReadString('\n')
+		choice, _ := in.ReadLine()
 
-		switch strings.TrimSpace(choice) {
+		switch choice {
 		case "1":
 			menu.PrintMenu()
 
 		case "2":
-			menu.AddItem()
+			if err := menu.AddItem(); err != nil {
+				fmt.Println(err)
+			}
 		case "3":
 			break loop
 		default:
@@ -1,7 +1,9 @@
 package menu
 
-var data = menu{
-	{name: "Coffee", prices: map[string]float64{"Large": 1.60, "Medium": 1.50, "Small": 1.40}},
-	{name: "Tea", prices: map[string]float64{"Hot Tea": 1.50, "Milk Tea": 1.60, "Black Tea": 1.60}},
-	{name: "Iced Coffee", prices: map[string]float64{"Large": 1.70, "Medium": 1.60, "Small": 1.5}},
-}
+var data = Menu{items: []menuItem{
+	{name: "Coffee", category: "Drinks", prices: map[string]float64{"Large": 1.60, "Medium": 1.50, "Small": 1.40}},
+	{name: "Tea", category: "Drinks", prices: map[string]float64{"Hot Tea": 1.50, "Milk Tea": 1.60, "Black Tea": 1.60}},
+	{name: "Iced Coffee", category: "Drinks", prices: map[string]float64{"Large": 1.70, "Medium": 1.60, "Small": 1.5}},
+	{name: "Croissant", category: "Food", prices: map[string]float64{"Plain": 2.20, "Almond": 2.80}},
+	{name: "Bagel", category: "Food", prices: map[string]float64{"Plain": 1.80, "Cream Cheese": 2.50}},
+}}
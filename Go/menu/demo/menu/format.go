@@ -0,0 +1,69 @@
+package menu
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PriceFormatter renders prices, e.g. "$1.60" or "€1,60"
+type PriceFormatter struct {
+	Symbol       string
+	ThousandsSep string
+	DecimalSep   string
+	// Places is the number of decimal places; 0 means the default of two and
+	// NoDecimals prints whole amounts
+	Places            int
+	SymbolAfterAmount bool
+}
+
+// NoDecimals is the Places value for prices without a fractional part, e.g. "¥1,500"
+const NoDecimals = -1
+
+// DefaultPriceFormatter prints a bare number with two decimal places
+var DefaultPriceFormatter = PriceFormatter{DecimalSep: ".", Places: 2}
+
+// Format renders price. The zero PriceFormatter behaves like DefaultPriceFormatter.
+func (f PriceFormatter) Format(price float64) string {
+	if f == (PriceFormatter{}) {
+		f = DefaultPriceFormatter
+	}
+	if f.DecimalSep == "" {
+		f.DecimalSep = "."
+	}
+	switch {
+	case f.Places == 0:
+		f.Places = DefaultPriceFormatter.Places
+	case f.Places < 0:
+		f.Places = 0
+	}
+
+	neg := price < 0
+	s := strconv.FormatFloat(math.Abs(price), 'f', f.Places, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	if f.ThousandsSep != "" {
+		var b strings.Builder
+		for i, r := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.ThousandsSep)
+			}
+			b.WriteRune(r)
+		}
+		whole = b.String()
+	}
+
+	amount := whole
+	if frac != "" {
+		amount += f.DecimalSep + frac
+	}
+	if f.SymbolAfterAmount {
+		amount += f.Symbol
+	} else {
+		amount = f.Symbol + amount
+	}
+	if neg {
+		amount = "-" + amount
+	}
+	return amount
+}
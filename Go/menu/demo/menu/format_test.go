@@ -0,0 +1,74 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPriceFormatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		f     PriceFormatter
+		price float64
+		want  string
+	}{
+		{"default", PriceFormatter{}, 1.6, "1.60"},
+		{"dollars", PriceFormatter{Symbol: "$", ThousandsSep: ",", DecimalSep: ".", Places: 2}, 1.6, "$1.60"},
+		{"dollars-thousands", PriceFormatter{Symbol: "$", ThousandsSep: ",", DecimalSep: ".", Places: 2}, 1234567.891, "$1,234,567.89"},
+		{"euros", PriceFormatter{Symbol: "€", ThousandsSep: ".", DecimalSep: ",", Places: 2}, 1.6, "€1,60"},
+		{"euros-suffix", PriceFormatter{Symbol: " €", ThousandsSep: ".", DecimalSep: ",", Places: 2, SymbolAfterAmount: true}, 1234.5, "1.234,50 €"},
+		{"zero-places", PriceFormatter{Symbol: "$"}, 2.5, "$2.50"},
+		{"no-decimals", PriceFormatter{Symbol: "¥", ThousandsSep: ",", Places: NoDecimals}, 1500, "¥1,500"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.Format(tt.price); got != tt.want {
+			t.Errorf("%s: expected %q but got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPrintUsesFormatter(t *testing.T) {
+	m := New([]Item{{Name: "Tea", Category: "Drinks", Sizes: []Size{{"Hot Tea", 1.5}}}})
+	m.Format = PriceFormatter{Symbol: "€", DecimalSep: ",", Places: 2}
+
+	var buf strings.Builder
+	m.print(&buf)
+	if out := buf.String(); !strings.Contains(out, "€1,50") {
+		t.Errorf("Expected the formatted price in %q", out)
+	}
+}
+
+func TestPrintColumnWidths(t *testing.T) {
+	m := New([]Item{{Name: "Coffee", Sizes: []Size{
+		{"Small", 1.40},
+		{"Extra Extra Large", 12.50},
+	}}})
+
+	var buf strings.Builder
+	m.print(&buf)
+
+	var rows []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasSuffix(line, "1.40") || strings.HasSuffix(line, "12.50") {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 size rows but got %q", buf.String())
+	}
+	if !strings.Contains(rows[0], " Extra Extra Large ") {
+		t.Errorf("Expected the long size name intact and padded, got %q", rows[0])
+	}
+	if len(rows[0]) != len(rows[1]) {
+		t.Errorf("Expected the rows to align, got %q and %q", rows[0], rows[1])
+	}
+
+	m.SizeWidth, m.PriceWidth = 20, 8
+	buf.Reset()
+	m.print(&buf)
+	if want := fmt.Sprintf("%20s%8s", "Small", "1.40"); !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected the explicit widths to give %q, got %q", want, buf.String())
+	}
+}
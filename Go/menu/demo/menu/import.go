@@ -0,0 +1,81 @@
+package menu
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ImportJSON reads a menu written as a JSON array of items, e.g.
+//
+//	[{"name": "Coffee", "category": "Drinks", "sizes": [{"name": "Small", "price": 1.40}]}]
+func ImportJSON(r io.Reader) (Menu, error) {
+	var items []Item
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return Menu{}, fmt.Errorf("reading menu JSON: %w", err)
+	}
+	return New(items), nil
+}
+
+// ImportCSV reads a menu with one size per row: name,category,size,price.
+// Rows for the same item are merged, and a header row starting with "name" is
+// skipped.
+func ImportCSV(r io.Reader) (Menu, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return Menu{}, fmt.Errorf("reading menu CSV: %w", err)
+	}
+
+	var items []Item
+	index := map[string]int{}
+	for i, row := range rows {
+		if i == 0 && strings.EqualFold(row[0], "name") {
+			continue
+		}
+		price, err := strconv.ParseFloat(row[3], 64)
+		if err != nil {
+			return Menu{}, fmt.Errorf("reading menu CSV: line %d: invalid price %q", i+1, row[3])
+		}
+
+		at, ok := index[row[0]]
+		if !ok {
+			at = len(items)
+			index[row[0]] = at
+			items = append(items, Item{Name: row[0], Category: row[1]})
+		}
+		items[at].Sizes = append(items[at].Sizes, Size{Name: row[2], Price: price})
+	}
+	return New(items), nil
+}
+
+// LoadFile replaces the menu with the one in path, read as CSV when the file
+// ends in .csv and as JSON otherwise. Only the items are replaced; settings
+// such as Format and Rounding are kept. If the file can't be loaded the menu is
+// left empty and the error returned, so the demo still starts.
+func LoadFile(path string) error {
+	m, err := importFile(path)
+	data.items = m.items
+	return err
+}
+
+func importFile(path string) (Menu, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Menu{}, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ImportCSV(f)
+	}
+	return ImportJSON(f)
+}
@@ -0,0 +1,84 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportCSV(t *testing.T) {
+	m, err := ImportCSV(strings.NewReader("name,category,size,price\nCoffee,Drinks,Small,1.40\nMuffin,Food,Plain,2.00\nCoffee,Drinks,Large,1.60\n"))
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	want := []Item{
+		{Name: "Coffee", Category: "Drinks", Sizes: []Size{{"Large", 1.60}, {"Small", 1.40}}},
+		{Name: "Muffin", Category: "Food", Sizes: []Size{{"Plain", 2.00}}},
+	}
+	if got := m.Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v but got %v", want, got)
+	}
+
+	if _, err := ImportCSV(strings.NewReader("Coffee,Drinks,Small,cheap\n")); err == nil {
+		t.Error("Expected an error for an invalid price")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	saved := data
+	t.Cleanup(func() { data = saved })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "menu.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"Scone","category":"Food","sizes":[{"name":"Plain","price":2.5}]}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	want := []Item{{Name: "Scone", Category: "Food", Sizes: []Size{{"Plain", 2.5}}}}
+	if got := data.Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the loaded menu %v but got %v", want, got)
+	}
+
+	if err := LoadFile(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a not-exist error but got %v", err)
+	}
+	if got := data.Items(); len(got) != 0 {
+		t.Errorf("Expected an empty menu after a failed load but got %v", got)
+	}
+}
+
+func TestLoadFileKeepsSettings(t *testing.T) {
+	saved := data
+	t.Cleanup(func() { data = saved })
+
+	path := filepath.Join(t.TempDir(), "menu.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"Scone","sizes":[{"name":"Plain","price":2.5}]}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data.Rounding = UpToCents(5)
+	data.SizeWidth, data.PriceWidth = 12, 8
+	data.Capacity = 5
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	if data.Rounding == nil || data.SizeWidth != 12 || data.PriceWidth != 8 || data.Capacity != 5 {
+		t.Fatalf("Expected the menu settings to survive the load, got %+v", data)
+	}
+	if err := ApplyPriceChange(context.Background(), 3, 1); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if got := data.Items()[0].Sizes[0].Price; got != 2.60 {
+		t.Errorf("Expected 2.5 +3%% rounded up to 2.60 but got %v", got)
+	}
+}
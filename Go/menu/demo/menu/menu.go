@@ -1,44 +1,269 @@
 package menu
 
 import (
-	"bufio"
+	"demo/input"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
-var in = bufio.NewReader(os.Stdin)
+var in = input.NewLineReader(os.Stdin)
+
+// EmptyMessage is printed in place of the menu when it has no items
+const EmptyMessage = "Menu is empty"
+
+// DefaultCategory is used for items added without a category
+const DefaultCategory = "Other"
 
 type menuItem struct {
-	name   string
-	prices map[string]float64
+	name     string
+	category string
+	prices   map[string]float64
+
+	// soldOut holds the sizes marked unavailable; nil means all are available
+	soldOut map[string]bool
+}
+
+// Menu is a list of items, each with a price per size
+type Menu struct {
+	items []menuItem
+
+	// Format controls how prices are printed; the zero value prints a bare
+	// number with two decimal places
+	Format PriceFormatter
+
+	// Rounding is applied to every price changed on the menu; nil rounds to
+	// the nearest cent
+	Rounding RoundingPolicy
+
+	// Capacity is the most items the menu can hold; zero means unlimited
+	Capacity int
+
+	// SizeWidth and PriceWidth set the printed column widths. Zero widens the
+	// column to fit its longest entry, and never below minColumnWidth.
+	SizeWidth  int
+	PriceWidth int
+}
+
+// minColumnWidth is the narrowest computed column
+const minColumnWidth = 10
+
+// ErrMenuFull is returned when adding an item to a menu at its capacity
+var ErrMenuFull = errors.New("menu is full")
+
+// ErrItemNotFound and ErrSizeNotFound are returned by SetAvailable for an item
+// or size the menu doesn't have
+var (
+	ErrItemNotFound = errors.New("item not found")
+	ErrSizeNotFound = errors.New("size not found")
+)
+
+// SoldOutMark is printed after the price of an unavailable size
+const SoldOutMark = "(sold out)"
+
+// Size is one priced size of an item
+type Size struct {
+	Name  string
+	Price float64
+}
+
+// Item is a menu item with its sizes sorted by name
+type Item struct {
+	Name     string
+	Category string
+	Sizes    []Size
+}
+
+// Section is a named group of items, such as "Drinks" or "Food"
+type Section struct {
+	Name  string
+	Items []Item
+}
+
+// New builds a Menu from items
+func New(items []Item) Menu {
+	m := Menu{items: make([]menuItem, 0, len(items))}
+	for _, item := range items {
+		prices := make(map[string]float64, len(item.Sizes))
+		for _, s := range item.Sizes {
+			prices[s.Name] = s.Price
+		}
+		m.items = append(m.items, menuItem{name: item.Name, category: item.Category, prices: prices})
+	}
+	return m
+}
+
+// Default returns a copy of the menu the demo starts with
+func Default() Menu {
+	return New(data.Items())
+}
+
+// Items returns the menu in order, with each item's sizes sorted by name
+func (m Menu) Items() []Item {
+	items := make([]Item, 0, len(m.items))
+	for _, mi := range m.items {
+		item := Item{Name: mi.name, Category: mi.category, Sizes: make([]Size, 0, len(mi.prices))}
+		for size, price := range mi.prices {
+			item.Sizes = append(item.Sizes, Size{Name: size, Price: price})
+		}
+		sort.Slice(item.Sizes, func(i, j int) bool { return item.Sizes[i].Name < item.Sizes[j].Name })
+		items = append(items, item)
+	}
+	return items
+}
+
+// Sections groups the menu by category, with categories and the items in each
+// sorted by name. Items without a category go under DefaultCategory.
+func (m Menu) Sections() []Section {
+	byName := map[string]*Section{}
+	var sections []*Section
+	for _, item := range m.Items() {
+		if item.Category == "" {
+			item.Category = DefaultCategory
+		}
+		s, ok := byName[item.Category]
+		if !ok {
+			s = &Section{Name: item.Category}
+			byName[item.Category] = s
+			sections = append(sections, s)
+		}
+		s.Items = append(s.Items, item)
+	}
+
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Name < sections[j].Name })
+
+	out := make([]Section, 0, len(sections))
+	for _, s := range sections {
+		sort.SliceStable(s.Items, func(i, j int) bool { return s.Items[i].Name < s.Items[j].Name })
+		out = append(out, *s)
+	}
+	return out
 }
 
-type menu []menuItem
+func (m Menu) print(w io.Writer) {
+	if len(m.items) == 0 {
+		fmt.Fprintln(w, EmptyMessage)
+		return
+	}
+
+	sections := m.Sections()
+	sizeWidth, priceWidth := m.columnWidths(sections)
+	for _, section := range sections {
+		fmt.Fprintf(w, "== %s ==\n", section.Name)
+		for _, item := range section.Items {
+			fmt.Fprintln(w, item.Name)
+			fmt.Fprintln(w, strings.Repeat("-", 10))
+			for _, size := range item.Sizes {
+				fmt.Fprintf(w, "%*s%*s", sizeWidth, size.Name, priceWidth, m.Format.Format(size.Price))
+				if !m.Available(item.Name, size.Name) {
+					fmt.Fprint(w, " ", SoldOutMark)
+				}
+				fmt.Fprintln(w)
+			}
+		}
+	}
+}
+
+// columnWidths returns the size and price column widths, using the explicit
+// widths when set. Computed widths leave at least one space before each entry
+// so neighbouring columns never run together.
+func (m Menu) columnWidths(sections []Section) (sizeWidth, priceWidth int) {
+	sizeWidth, priceWidth = minColumnWidth, minColumnWidth
+	for _, section := range sections {
+		for _, item := range section.Items {
+			for _, size := range item.Sizes {
+				sizeWidth = max(sizeWidth, utf8.RuneCountInString(size.Name)+1)
+				priceWidth = max(priceWidth, utf8.RuneCountInString(m.Format.Format(size.Price))+1)
+			}
+		}
+	}
+
+	if m.SizeWidth > 0 {
+		sizeWidth = m.SizeWidth
+	}
+	if m.PriceWidth > 0 {
+		priceWidth = m.PriceWidth
+	}
+	return sizeWidth, priceWidth
+}
 
-func (m menu) print() {
-	for _, item := range m {
-		fmt.Println(item.name)
-		fmt.Println(strings.Repeat("-", 10))
-		for size, price := range item.prices {
-			fmt.Printf("%10s%10.2f\n", size, price)
+// find returns the item called name, or nil
+func (m *Menu) find(name string) *menuItem {
+	for i := range m.items {
+		if m.items[i].name == name {
+			return &m.items[i]
 		}
 	}
+	return nil
 }
 
-func (m *menu) addItem() {
+// Available reports whether size of item can be ordered. Sizes are available
+// until marked otherwise with SetAvailable.
+func (m Menu) Available(item, size string) bool {
+	mi := m.find(item)
+	return mi == nil || !mi.soldOut[size]
+}
+
+// SetAvailable marks size of item as available or sold out. Sold out sizes
+// keep their price and are still printed, marked with SoldOutMark.
+func (m *Menu) SetAvailable(item, size string, avail bool) error {
+	mi := m.find(item)
+	if mi == nil {
+		return fmt.Errorf("%w: %q", ErrItemNotFound, item)
+	}
+	if _, ok := mi.prices[size]; !ok {
+		return fmt.Errorf("%w: %q has no size %q", ErrSizeNotFound, item, size)
+	}
+
+	if avail {
+		delete(mi.soldOut, size)
+		return nil
+	}
+	if mi.soldOut == nil {
+		mi.soldOut = make(map[string]bool)
+	}
+	mi.soldOut[size] = true
+	return nil
+}
+
+func (m *Menu) addItem() error {
+	if m.Capacity > 0 && len(m.items) >= m.Capacity {
+		return fmt.Errorf("%w: it holds at most %d items", ErrMenuFull, m.Capacity)
+	}
+
 	fmt.Println("Please enter the items that you want to add to the list")
-	name, _ := in.ReadString('\n')
-	*m = append(*m, menuItem{name: name, prices: make(map[string]float64)})
+	name, _ := in.ReadLine()
+	fmt.Printf("Please enter the category (default %s)\n", DefaultCategory)
+	category, _ := in.ReadLine()
+
+	if category == "" {
+		category = DefaultCategory
+	}
+	m.items = append(m.items, menuItem{name: name, category: category, prices: make(map[string]float64)})
+	return nil
 }
 
-// AddItem Add item to the Menu
-func AddItem() {
-	data.addItem()
+// AddItem Add item to the Menu. It returns ErrMenuFull once the menu's
+// Capacity is reached.
+func AddItem() error {
+	return data.addItem()
+}
 
+// SetAvailable marks size of item on the menu as available or sold out
+func SetAvailable(item, size string, avail bool) error {
+	return data.SetAvailable(item, size, avail)
 }
 
 // PrintMenu Display the data
 func PrintMenu() {
-	data.print()
+	PrintMenuTo(os.Stdout)
+}
+
+// PrintMenuTo writes the menu to w in the same format as PrintMenu
+func PrintMenuTo(w io.Writer) {
+	data.print(w)
 }
@@ -0,0 +1,184 @@
+package menu
+
+import (
+	"bytes"
+	"demo/input"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestItemsSortsSizes(t *testing.T) {
+	m := New([]Item{{Name: "Coffee", Sizes: []Size{{"Small", 1.40}, {"Large", 1.60}, {"Medium", 1.50}}}})
+
+	want := []Item{{Name: "Coffee", Sizes: []Size{{"Large", 1.60}, {"Medium", 1.50}, {"Small", 1.40}}}}
+	if got := m.Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v but got %v", want, got)
+	}
+}
+
+func TestDefaultIsACopy(t *testing.T) {
+	m := Default()
+	m.items[0].prices["Large"] = 99
+
+	if data.items[0].prices["Large"] == 99 {
+		t.Error("Expected Default to return a copy of the menu data")
+	}
+}
+
+// captureStdout returns what f writes to os.Stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestPrintGroupsBySection(t *testing.T) {
+	m := New([]Item{
+		{Name: "Tea", Category: "Drinks", Sizes: []Size{{"Hot Tea", 1.50}}},
+		{Name: "Bagel", Category: "Food", Sizes: []Size{{"Plain", 1.80}}},
+		{Name: "Coffee", Category: "Drinks", Sizes: []Size{{"Small", 1.40}}},
+		{Name: "Water"},
+	})
+
+	var buf strings.Builder
+	m.print(&buf)
+	out := buf.String()
+
+	var order []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "==") || line == "Tea" || line == "Bagel" || line == "Coffee" || line == "Water" {
+			order = append(order, line)
+		}
+	}
+
+	want := []string{"== Drinks ==", "Coffee", "Tea", "== Food ==", "Bagel", "== Other ==", "Water"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected %v but got %v", want, order)
+	}
+}
+
+func TestPrintEmptyMenu(t *testing.T) {
+	tests := []struct {
+		name  string
+		menu  Menu
+		empty bool
+	}{
+		{"empty", Menu{}, true},
+		{"loaded-empty", New(nil), true},
+		{"with-items", New([]Item{{Name: "Coffee", Sizes: []Size{{"Small", 1.40}}}}), false},
+	}
+
+	for _, tt := range tests {
+		var buf strings.Builder
+		tt.menu.print(&buf)
+
+		if got := strings.Contains(buf.String(), EmptyMessage); got != tt.empty {
+			t.Errorf("%s: expected %q in the output to be %v, got %q", tt.name, EmptyMessage, tt.empty, buf.String())
+		}
+	}
+}
+
+func TestSetAvailable(t *testing.T) {
+	m := New([]Item{{Name: "Coffee", Sizes: []Size{{"Small", 1.40}, {"Large", 1.60}}}})
+
+	if err := m.SetAvailable("Coffee", "Large", false); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if m.Available("Coffee", "Large") || !m.Available("Coffee", "Small") {
+		t.Error("Expected only Large to be sold out")
+	}
+
+	var buf strings.Builder
+	m.print(&buf)
+	out := buf.String()
+	if !strings.Contains(out, "1.60 "+SoldOutMark+"\n") {
+		t.Errorf("Expected Large to be marked %s, got %q", SoldOutMark, out)
+	}
+	if strings.Count(out, SoldOutMark) != 1 {
+		t.Errorf("Expected only one size to be marked, got %q", out)
+	}
+
+	if err := m.SetAvailable("Coffee", "Large", true); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	buf.Reset()
+	m.print(&buf)
+	if strings.Contains(buf.String(), SoldOutMark) {
+		t.Errorf("Expected Large to be available again, got %q", buf.String())
+	}
+
+	if err := m.SetAvailable("Muffin", "Large", false); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected ErrItemNotFound but got %v", err)
+	}
+	if err := m.SetAvailable("Coffee", "Venti", false); !errors.Is(err, ErrSizeNotFound) {
+		t.Errorf("Expected ErrSizeNotFound but got %v", err)
+	}
+}
+
+func TestPrintMenuTo(t *testing.T) {
+	var buf bytes.Buffer
+	PrintMenuTo(&buf)
+
+	out := buf.String()
+	for _, want := range []string{"== Drinks ==", "Coffee", "Iced Coffee", "== Food ==", "Croissant", "----------"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the menu output %q", want, out)
+		}
+	}
+
+	if stdout := captureStdout(t, PrintMenu); stdout != out {
+		t.Errorf("Expected PrintMenu to write %q to stdout but got %q", out, stdout)
+	}
+}
+
+func TestAddItemCapacity(t *testing.T) {
+	stdin := in
+	defer func() { in = stdin }()
+	in = input.NewLineReader(strings.NewReader("Muffin\nFood\nJuice\n\nScone\nFood\n"))
+
+	m := Menu{Capacity: 2}
+	captureStdout(t, func() {
+		for i := 0; i < 2; i++ {
+			if err := m.addItem(); err != nil {
+				t.Errorf("Got an error when should not have: %v", err)
+			}
+		}
+		if err := m.addItem(); !errors.Is(err, ErrMenuFull) {
+			t.Errorf("Expected ErrMenuFull but got %v", err)
+		}
+	})
+
+	var names []string
+	for _, item := range m.Items() {
+		names = append(names, item.Name+"/"+item.Category)
+	}
+	if want := []string{"Muffin/Food", "Juice/" + DefaultCategory}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v but got %v", want, names)
+	}
+
+	unlimited := Menu{}
+	in = input.NewLineReader(strings.NewReader("Scone\nFood\n"))
+	captureStdout(t, func() {
+		if err := unlimited.addItem(); err != nil {
+			t.Errorf("Expected a zero capacity to be unlimited, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package menu
+
+import (
+	"context"
+	"sync"
+)
+
+// ApplyPriceChange changes every price on the menu by pct percent, e.g. 10
+// for a 10% rise or -5 for a 5% cut.
+func ApplyPriceChange(ctx context.Context, pct float64, workers int) error {
+	return data.applyPriceChange(ctx, pct, workers)
+}
+
+// applyPriceChange reprices the items with a pool of workers, each taking one
+// item at a time. The new prices are built in fresh maps and only swapped in
+// once every item is done, so a cancelled change leaves the menu untouched.
+// Prices are rounded by the menu's Rounding policy.
+func (m *Menu) applyPriceChange(ctx context.Context, pct float64, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	factor := 1 + pct/100
+
+	updated := make([]map[string]float64, len(m.items))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				prices := make(map[string]float64, len(m.items[i].prices))
+				for size, price := range m.items[i].prices {
+					prices[size] = m.round(price * factor)
+				}
+				updated[i] = prices
+			}
+		}()
+	}
+
+feed:
+	for i := range m.items {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i, prices := range updated {
+		m.items[i].prices = prices
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package menu
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestApplyPriceChange(t *testing.T) {
+	m := Default()
+	before := m.Items()
+
+	if err := m.applyPriceChange(context.Background(), 10, 3); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	after := m.Items()
+	for i, item := range before {
+		for j, size := range item.Sizes {
+			want := size.Price * 1.1
+			if got := after[i].Sizes[j].Price; math.Abs(got-want) > 0.005 {
+				t.Errorf("%s %s: expected %.2f but got %.2f", item.Name, size.Name, want, got)
+			}
+		}
+	}
+}
+
+func TestApplyPriceChangeCancelled(t *testing.T) {
+	m := Default()
+	before := m.Items()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.applyPriceChange(ctx, 50, 2); err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+
+	for i, item := range m.Items() {
+		for j, size := range item.Sizes {
+			if size.Price != before[i].Sizes[j].Price {
+				t.Errorf("Expected a cancelled change to leave %s %s at %.2f, got %.2f", item.Name, size.Name, before[i].Sizes[j].Price, size.Price)
+			}
+		}
+	}
+}
+
+func TestApplyPriceChangeRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RoundingPolicy
+		pct      float64
+		expected []float64
+	}{
+		{"default", nil, 10, []float64{1.65, 1.54, 1.98, 2.75}},
+		{"nearest-cent", RoundNearestCent, 10, []float64{1.65, 1.54, 1.98, 2.75}},
+		{"nearest-5c", NearestCents(5), 10, []float64{1.65, 1.55, 2.00, 2.75}},
+		{"up-to-5c", UpToCents(5), 10, []float64{1.65, 1.55, 2.00, 2.75}},
+		{"up-to-5c-cut", UpToCents(5), -3, []float64{1.50, 1.40, 1.75, 2.45}},
+		{"down-to-10c", DownToCents(10), 10, []float64{1.60, 1.50, 1.90, 2.70}},
+	}
+
+	for _, tt := range tests {
+		m := New([]Item{{Name: "Coffee", Sizes: []Size{{"A", 1.50}, {"B", 1.40}, {"C", 1.80}, {"D", 2.50}}}})
+		m.Rounding = tt.policy
+
+		if err := m.applyPriceChange(context.Background(), tt.pct, 2); err != nil {
+			t.Fatalf("%s: got an error when should not have: %v", tt.name, err)
+		}
+
+		for i, size := range m.Items()[0].Sizes {
+			if size.Price != tt.expected[i] {
+				t.Errorf("%s: expected size %s to be %v but got %v", tt.name, size.Name, tt.expected[i], size.Price)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package menu
+
+import "math"
+
+// RoundingPolicy rounds a price after it has been changed, e.g. to the
+// nearest cent or up to the next 5 cents
+type RoundingPolicy func(price float64) float64
+
+// RoundNearestCent is the policy used when a menu doesn't set one
+var RoundNearestCent = NearestCents(1)
+
+// NearestCents rounds prices to the nearest multiple of step cents, halves
+// rounding away from zero. NearestCents(5) turns 1.62 into 1.60 and 1.63
+// into 1.65.
+func NearestCents(step int) RoundingPolicy {
+	return func(price float64) float64 {
+		return roundToCents(price, step, math.Round)
+	}
+}
+
+// UpToCents rounds prices up to the next multiple of step cents, so
+// UpToCents(5) turns 1.61 into 1.65. Prices already on a multiple are kept.
+func UpToCents(step int) RoundingPolicy {
+	return func(price float64) float64 {
+		return roundToCents(price, step, math.Ceil)
+	}
+}
+
+// DownToCents rounds prices down to the previous multiple of step cents
+func DownToCents(step int) RoundingPolicy {
+	return func(price float64) float64 {
+		return roundToCents(price, step, math.Floor)
+	}
+}
+
+// roundToCents counts price in units of step cents and rounds that with
+// round. The count is first cut to six places so float noise, as in
+// 1.6500000001, doesn't push a price that is already on a step over it.
+func roundToCents(price float64, step int, round func(float64) float64) float64 {
+	if step < 1 {
+		step = 1
+	}
+	units := price * 100 / float64(step)
+	units = math.Round(units*1e6) / 1e6
+	return round(units) * float64(step) / 100
+}
+
+// round applies the menu's rounding policy, or RoundNearestCent if it has none
+func (m *Menu) round(price float64) float64 {
+	if m.Rounding == nil {
+		return RoundNearestCent(price)
+	}
+	return m.Rounding(price)
+}
+
+// SetRounding sets the rounding policy of the menu ApplyPriceChange updates
+func SetRounding(p RoundingPolicy) {
+	data.Rounding = p
+}
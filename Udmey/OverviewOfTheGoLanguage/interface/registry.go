@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// constructors maps a species name to a function returning a new Animal of that species
+var constructors = map[string]func() Animal{}
+
+func init() {
+	Register("dog", func() Animal { return &Dog{Name: "Dog", woof: woof} })
+	Register("cat", func() Animal { return &Cat{Name: "Cat"} })
+}
+
+// Register makes an Animal available to New under the species name.
+// Registering a name again replaces the earlier constructor.
+func Register(species string, constructor func() Animal) {
+	constructors[species] = constructor
+}
+
+// New returns a new Animal of the named species
+func New(species string) (Animal, error) {
+	constructor, ok := constructors[species]
+	if !ok {
+		return nil, fmt.Errorf("unknown species %q, want one of %v", species, Species())
+	}
+	return constructor(), nil
+}
+
+// Species returns the registered species names in order
+func Species() []string {
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
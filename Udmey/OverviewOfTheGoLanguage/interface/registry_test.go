@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		species string
+		say     string
+		legs    int
+	}{
+		{"dog", "Woof", 4},
+		{"cat", "Meo", 4},
+	}
+
+	for _, tt := range tests {
+		a, err := New(tt.species)
+		if err != nil {
+			t.Errorf("%s: got an error when should not have: %v", tt.species, err)
+			continue
+		}
+		if a.say() != tt.say || a.NumberOfLegs() != tt.legs {
+			t.Errorf("%s: expected %s with %d legs but got %s with %d", tt.species, tt.say, tt.legs, a.say(), a.NumberOfLegs())
+		}
+	}
+
+	if want := []string{"cat", "dog"}; !reflect.DeepEqual(Species(), want) {
+		t.Errorf("Expected species %v but got %v", want, Species())
+	}
+}
+
+func TestNewUnknownSpecies(t *testing.T) {
+	if a, err := New("unicorn"); err == nil {
+		t.Errorf("Expected an error for an unknown species but got %v", a)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("puppy", func() Animal { return &Dog{Name: "Puppy"} })
+	defer delete(constructors, "puppy")
+
+	a, err := New("puppy")
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if d, ok := a.(*Dog); !ok || d.Name != "Puppy" {
+		t.Errorf("Expected the registered puppy but got %#v", a)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+// TotalLegs returns the number of legs across all the animals
+func TotalLegs(animals []Animal) int {
+	total := 0
+	for _, a := range animals {
+		total += a.NumberOfLegs()
+	}
+	return total
+}
+
+// Sounds returns what each animal says, in order. It is empty, not nil, for
+// no animals.
+func Sounds(animals []Animal) []string {
+	sounds := make([]string, 0, len(animals))
+	for _, a := range animals {
+		sounds = append(sounds, a.say())
+	}
+	return sounds
+}
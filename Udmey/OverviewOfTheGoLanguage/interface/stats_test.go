@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTotalLegsAndSounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		animals []Animal
+		legs    int
+		sounds  []string
+	}{
+		{"nil", nil, 0, []string{}},
+		{"empty", []Animal{}, 0, []string{}},
+		{"mixed", []Animal{&Dog{Name: "Rex"}, &Cat{Name: "Tom"}, &Dog{Name: "Fido"}}, 12, []string{"Woof", "Meo", "Woof"}},
+	}
+
+	for _, tt := range tests {
+		if got := TotalLegs(tt.animals); got != tt.legs {
+			t.Errorf("%s: expected %d legs but got %d", tt.name, tt.legs, got)
+		}
+		if got := Sounds(tt.animals); !reflect.DeepEqual(got, tt.sounds) {
+			t.Errorf("%s: expected sounds %v but got %v", tt.name, tt.sounds, got)
+		}
+	}
+}
@@ -1,18 +1,195 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"os/exec"
+	"strings"
 )
 
-func main() {
+var (
+	kubectlArgs = []string{"get", "svc", "-n", "bah-dev"}
+	gcloudArgs  = []string{"auth", "login"}
+)
+
+// CommandRunner runs an external command and returns its standard output
+type CommandRunner interface {
+	Run(name string, args ...string) ([]byte, error)
+}
 
-	out, err := exec.Command("kubectl", "get", "svc", "-n", "bah-dev").Output()
+type execRunner struct{}
 
+func (execRunner) Run(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// buildKubectlArgs appends -o for the json and yaml output formats; table is
+// kubectl's default and needs no flag
+func buildKubectlArgs(output string) ([]string, error) {
+	args := append([]string(nil), kubectlArgs...)
+	switch output {
+	case "", "table":
+		return args, nil
+	case "json", "yaml":
+		return append(args, "-o", output), nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (want table, json or yaml)", output)
+}
+
+// serviceList is the part of `kubectl get svc -o json` the wrapper reports
+type serviceList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Type      string `json:"type"`
+			ClusterIP string `json:"clusterIP"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// printServices writes the name and cluster IP of each service in a kubectl JSON list
+func printServices(w io.Writer, data []byte) error {
+	svcs, err := parseServiceJSON(data)
 	if err != nil {
+		return err
+	}
+	for _, svc := range svcs {
+		fmt.Fprintf(w, "%s\t%s\n", svc.Name, svc.ClusterIP)
+	}
+	return nil
+}
+
+// service is the summary of a kubectl service printed by -format json
+type service struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	ClusterIP string `json:"clusterIP,omitempty"`
+}
+
+// parseServiceJSON reads the services out of `kubectl get svc -o json`
+func parseServiceJSON(data []byte) ([]service, error) {
+	var list serviceList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing kubectl output: %w", err)
+	}
+	svcs := make([]service, 0, len(list.Items))
+	for _, item := range list.Items {
+		svcs = append(svcs, service{Name: item.Metadata.Name, Type: item.Spec.Type, ClusterIP: item.Spec.ClusterIP})
+	}
+	return svcs, nil
+}
+
+// parseServiceTable reads the services out of kubectl's default table, whose
+// header starts with NAME TYPE CLUSTER-IP
+func parseServiceTable(data []byte) ([]service, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		return []service{}, nil
+	}
+
+	header := strings.Fields(lines[0])
+	if len(header) < 3 || header[0] != "NAME" || header[1] != "TYPE" || header[2] != "CLUSTER-IP" {
+		return nil, fmt.Errorf("parsing kubectl output: unexpected header %q", lines[0])
+	}
+
+	svcs := make([]service, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("parsing kubectl output: short line %q", line)
+		}
+		svcs = append(svcs, service{Name: fields[0], Type: fields[1], ClusterIP: fields[2]})
+	}
+	return svcs, nil
+}
+
+// writeServices prints svcs as one name per line for "names", or as a JSON
+// array for "json"
+func writeServices(w io.Writer, format string, svcs []service) error {
+	switch format {
+	case "names":
+		for _, svc := range svcs {
+			fmt.Fprintln(w, svc.Name)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(svcs)
+	}
+	return fmt.Errorf("unknown format %q (want raw, names or json)", format)
+}
+
+func commandLine(name string, args []string) string {
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+func run(args []string, runner CommandRunner, stdout io.Writer) error {
+	fs := flag.NewFlagSet("kubectl", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the commands that would run without running them")
+	output := fs.String("output", "table", "output format: table, json or yaml")
+	format := fs.String("format", "raw", "summary format: raw prints kubectl's output, names one service name per line, json a list of services")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "raw":
+	case "names", "json":
+		if *output == "yaml" {
+			return fmt.Errorf("-format %s needs -output table or json", *format)
+		}
+	default:
+		return fmt.Errorf("unknown format %q (want raw, names or json)", *format)
+	}
+
+	kargs, err := buildKubectlArgs(*output)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintln(stdout, commandLine("kubectl", kargs))
+		fmt.Fprintln(stdout, "on failure:", commandLine("gcloud", gcloudArgs))
+		return nil
+	}
+
+	out, err := runner.Run("kubectl", kargs...)
+	if err != nil {
+		if _, loginErr := runner.Run("gcloud", gcloudArgs...); loginErr != nil {
+			return fmt.Errorf("%v (gcloud login also failed: %v)", err, loginErr)
+		}
+		return err
+	}
+
+	if *format != "raw" {
+		var svcs []service
+		if *output == "json" {
+			svcs, err = parseServiceJSON(out)
+		} else {
+			svcs, err = parseServiceTable(out)
+		}
+		if err != nil {
+			return err
+		}
+		return writeServices(stdout, *format, svcs)
+	}
+
+	if *output == "json" {
+		return printServices(stdout, out)
+	}
+	fmt.Fprint(stdout, string(out))
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], execRunner{}, os.Stdout); err != nil {
 		log.Fatal(err)
-		out, err := exec.Command("gcloud", "auth", "login").Output()
 	}
-	fmt.Printf(string(out))
 }
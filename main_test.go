@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeRunner struct {
+	calls []string
+	out   map[string][]byte
+	err   map[string]error
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, commandLine(name, args))
+	return f.out[name], f.err[name]
+}
+
+func TestRunDryRun(t *testing.T) {
+	runner := &fakeRunner{}
+	var out strings.Builder
+
+	if err := run([]string{"-dry-run"}, runner, &out); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	want := "kubectl get svc -n bah-dev\non failure: gcloud auth login\n"
+	if out.String() != want {
+		t.Errorf("Expected %q but got %q", want, out.String())
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("Expected no commands to run but got %v", runner.calls)
+	}
+}
+
+func TestRunFallsBackToLogin(t *testing.T) {
+	runner := &fakeRunner{err: map[string]error{"kubectl": errors.New("unauthorized")}}
+
+	if err := run(nil, runner, &strings.Builder{}); err == nil {
+		t.Error("Expected the kubectl error to be returned")
+	}
+	if len(runner.calls) != 2 || runner.calls[1] != "gcloud auth login" {
+		t.Errorf("Expected kubectl then gcloud login but got %v", runner.calls)
+	}
+}
+
+func TestBuildKubectlArgs(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+		isErr  bool
+	}{
+		{"table", "get svc -n bah-dev", false},
+		{"json", "get svc -n bah-dev -o json", false},
+		{"yaml", "get svc -n bah-dev -o yaml", false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		args, err := buildKubectlArgs(tt.output)
+		if tt.isErr {
+			if err == nil {
+				t.Errorf("%s: expected an error but didn't get one", tt.output)
+			}
+			continue
+		}
+		if got := strings.Join(args, " "); got != tt.want {
+			t.Errorf("%s: expected %q but got %q", tt.output, tt.want, got)
+		}
+	}
+}
+
+const sampleServices = `{
+	"apiVersion": "v1",
+	"kind": "List",
+	"items": [
+		{"metadata": {"name": "api", "namespace": "bah-dev"}, "spec": {"clusterIP": "10.0.0.10", "type": "ClusterIP"}},
+		{"metadata": {"name": "web", "namespace": "bah-dev"}, "spec": {"clusterIP": "10.0.0.11", "type": "ClusterIP"}}
+	]
+}`
+
+func TestRunJSONOutput(t *testing.T) {
+	runner := &fakeRunner{out: map[string][]byte{"kubectl": []byte(sampleServices)}}
+	var out strings.Builder
+
+	if err := run([]string{"-output", "json"}, runner, &out); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	want := "api\t10.0.0.10\nweb\t10.0.0.11\n"
+	if out.String() != want {
+		t.Errorf("Expected %q but got %q", want, out.String())
+	}
+	if runner.calls[0] != "kubectl get svc -n bah-dev -o json" {
+		t.Errorf("Unexpected command %q", runner.calls[0])
+	}
+}
+
+const sampleTable = `NAME   TYPE           CLUSTER-IP   EXTERNAL-IP   PORT(S)        AGE
+api    ClusterIP      10.0.0.10    <none>        80/TCP         12d
+web    LoadBalancer   10.0.0.11    34.1.2.3      443:30443/TCP  3d
+`
+
+func TestParseServiceTable(t *testing.T) {
+	svcs, err := parseServiceTable([]byte(sampleTable))
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	want := []service{
+		{Name: "api", Type: "ClusterIP", ClusterIP: "10.0.0.10"},
+		{Name: "web", Type: "LoadBalancer", ClusterIP: "10.0.0.11"},
+	}
+	if len(svcs) != len(want) {
+		t.Fatalf("Expected %d services but got %d", len(want), len(svcs))
+	}
+	for i := range want {
+		if svcs[i] != want[i] {
+			t.Errorf("Expected %+v but got %+v", want[i], svcs[i])
+		}
+	}
+
+	if _, err := parseServiceTable([]byte("No resources found\n")); err == nil {
+		t.Error("Expected an error for an unexpected header")
+	}
+}
+
+func TestRunFormat(t *testing.T) {
+	tests := []struct {
+		args     []string
+		kubectl  string
+		expected string
+		isErr    bool
+	}{
+		{[]string{"-format", "names"}, sampleTable, "api\nweb\n", false},
+		{[]string{"-format", "names", "-output", "json"}, sampleServices, "api\nweb\n", false},
+		{[]string{"-format", "json"}, sampleTable, `[
+  {
+    "name": "api",
+    "type": "ClusterIP",
+    "clusterIP": "10.0.0.10"
+  },
+  {
+    "name": "web",
+    "type": "LoadBalancer",
+    "clusterIP": "10.0.0.11"
+  }
+]
+`, false},
+		{[]string{"-format", "json", "-output", "json"}, sampleServices, `[
+  {
+    "name": "api",
+    "type": "ClusterIP",
+    "clusterIP": "10.0.0.10"
+  },
+  {
+    "name": "web",
+    "type": "ClusterIP",
+    "clusterIP": "10.0.0.11"
+  }
+]
+`, false},
+		{[]string{"-format", "raw"}, sampleTable, sampleTable, false},
+		{[]string{"-format", "names", "-output", "yaml"}, "", "", true},
+		{[]string{"-format", "csv"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		runner := &fakeRunner{out: map[string][]byte{"kubectl": []byte(tt.kubectl)}}
+		var out strings.Builder
+
+		err := run(tt.args, runner, &out)
+		if tt.isErr {
+			if err == nil {
+				t.Errorf("%v: expected an error but didn't get one", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: got an error when should not have: %v", tt.args, err)
+			continue
+		}
+		if out.String() != tt.expected {
+			t.Errorf("%v: expected %q but got %q", tt.args, tt.expected, out.String())
+		}
+	}
+}
@@ -2,56 +2,250 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/anzx/pkg/opentelemetry"
 )
 
+// DefaultMaxN is the largest input NewApp accepts by default.
+const DefaultMaxN = 1000000
+
+// ErrReadTimeout is returned by Poll when no input arrives within ReadTimeout.
+var ErrReadTimeout = errors.New("timed out waiting for input")
+
+// ErrInvalidInput is returned by Poll for a line that is not a non-negative
+// integer. The line has been consumed, so the next Poll reads the line after.
+var ErrInvalidInput = errors.New("invalid input")
+
+// OutputFormat selects how App writes its results.
+type OutputFormat string
+
+const (
+	// OutputText writes human-readable log lines, e.g. "Fibonacci(7) = 13".
+	OutputText OutputFormat = "text"
+
+	// OutputNDJSON writes one JSON object per line, {"n":7,"result":13} or
+	// {"n":100,"error":"..."}, for piping into tools like jq.
+	OutputNDJSON OutputFormat = "ndjson"
+)
+
+// AppConfig holds the options for NewAppWithConfig. Zero fields take the
+// defaults used by NewApp.
+type AppConfig struct {
+	// Input is read by Poll, one number per line.
+	Input io.Reader
+
+	// Inputs are read in order after Input, each until EOF.
+	Inputs []io.Reader
+
+	// Output receives the results and skipped-input messages; nil uses the
+	// standard logger. With OutputNDJSON it receives only the JSON lines,
+	// defaulting to stdout, and other messages go to the standard logger.
+	Output io.Writer
+
+	// Format is how results are written; empty means OutputText.
+	Format OutputFormat
+
+	// Sink, when set, receives the results in place of Output and Format;
+	// prompts and other messages still go to Output.
+	Sink ResultSink
+
+	// MaxN is the largest n the app will compute; zero means DefaultMaxN.
+	MaxN uint
+
+	// ReadTimeout bounds how long Poll waits for a value; zero waits forever.
+	ReadTimeout time.Duration
+
+	// MaxInputs is how many values Run processes before returning; zero
+	// means no limit.
+	MaxInputs int
+
+	// Prompt is written to Output before each Poll, and the accepted value
+	// echoed after it; empty keeps the app silent for batch use.
+	Prompt string
+}
+
 type App struct {
-	r io.Reader
+	r   *lineSources
+	log *log.Logger
+
+	// sink receives every result and skipped input
+	sink ResultSink
+
+	// MaxN is the largest n the app will compute; larger inputs are logged
+	// and skipped.
+	MaxN uint
+
+	// ReadTimeout bounds how long Poll waits for a value; zero waits forever.
+	ReadTimeout time.Duration
+
+	// MaxInputs is how many values Run processes before returning nil; zero
+	// means Run continues until the input ends or ctx is cancelled.
+	MaxInputs int
+
+	// Prompt is shown before each Poll; empty disables prompting.
+	Prompt string
+
+	// pending holds a read that outlived its timeout, so the next Poll picks
+	// up its result instead of starting a second reader on the same input.
+	pending chan pollResult
+
+	// counters for Stats
+	started   time.Time
+	processed atomic.Int64
+	failed    atomic.Int64
 }
 
-func NewApp(r io.Reader) *App {
-	return &App{r: r}
+type pollResult struct {
+	n   uint
+	err error
 }
 
-func (a *App) Run(ctx context.Context) error {
+// NewApp returns an App reading from each of rs in turn with the default
+// configuration.
+func NewApp(rs ...io.Reader) *App {
+	return NewAppWithConfig(AppConfig{Inputs: rs})
+}
 
+// NewAppWithConfig returns an App configured by cfg.
+func NewAppWithConfig(cfg AppConfig) *App {
+	a := &App{r: newLineSources(append([]io.Reader{cfg.Input}, cfg.Inputs...)...), MaxN: cfg.MaxN, ReadTimeout: cfg.ReadTimeout, MaxInputs: cfg.MaxInputs, Prompt: cfg.Prompt, log: log.Default(), started: time.Now()}
+	if a.MaxN == 0 {
+		a.MaxN = DefaultMaxN
+	}
+	switch {
+	case cfg.Sink != nil:
+		a.sink = cfg.Sink
+	case cfg.Format == OutputNDJSON && cfg.Output != nil:
+		a.sink = NewJSONLinesSink(cfg.Output)
+	case cfg.Format == OutputNDJSON:
+		a.sink = NewJSONLinesSink(os.Stdout)
+	case cfg.Output != nil:
+		a.log = log.New(cfg.Output, "", 0)
+	}
+	if a.sink == nil {
+		a.sink = LogSink{Logger: a.log}
+	}
+	return a
+}
 
+func (a *App) Run(ctx context.Context) error {
 	ctx, spanEnd := opentelemetry.AddSpan(ctx, "App")
 	defer spanEnd()
 
-	for {
-		n, err := a.Poll(ctx)
+	processed := 0
+	for a.MaxInputs == 0 || processed < a.MaxInputs {
+		pollCtx, n, err := a.poll(ctx)
+		if errors.Is(err, ErrReadTimeout) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			a.log.Printf("Poll: %v\n", err)
+			continue
+		}
+		if errors.Is(err, ErrInvalidInput) {
+			a.failed.Add(1)
+			a.log.Printf("Poll: %v, skipping\n", err)
+			continue
+		}
 		if err != nil {
 			return err
 		}
+		processed++
+
+		if n > a.MaxN {
+			a.emit(n, 0, fmt.Errorf("input exceeds the maximum of %d, skipping", a.MaxN))
+			continue
+		}
 
-		a.Write(ctx, n)
+		// Write is traced as a child of the Poll that read n
+		a.Write(pollCtx, n)
 	}
+	return nil
 }
 
 func (a *App) Poll(ctx context.Context) (uint, error) {
-	_, spanEnd := opentelemetry.AddSpan(ctx, "App")
+	_, n, err := a.poll(ctx)
+	return n, err
+}
+
+// poll is Poll, also returning the context of its span so the work done with
+// the value can be traced as part of the read.
+func (a *App) poll(ctx context.Context) (context.Context, uint, error) {
+	ctx, spanEnd := opentelemetry.AddSpan(ctx, "Poll")
 	defer spanEnd()
 
-	log.Print("This what Fabicca would like to know")
+	n, err := a.read()
+	if a.Prompt != "" && err == nil {
+		fmt.Fprintf(a.log.Writer(), "%d\n", n)
+	}
+	return ctx, n, err
+}
 
-	var n uint
-	_, err := fmt.Fscanf(a.r, "%d\n", &n)
-	return n, err
+func (a *App) read() (uint, error) {
+	a.log.Print("This what Fabicca would like to know")
+
+	// a resumed read was already prompted for
+	if a.Prompt != "" && a.pending == nil {
+		fmt.Fprint(a.log.Writer(), a.Prompt)
+	}
+
+	if a.ReadTimeout <= 0 {
+		return a.scan()
+	}
+
+	// An io.Reader can't be interrupted, so a read that times out keeps its
+	// goroutine blocked until the reader returns. Rather than leak one per
+	// timeout, that read is parked in a.pending and reused by the next Poll.
+	if a.pending == nil {
+		ch := make(chan pollResult, 1)
+		go func() {
+			n, err := a.scan()
+			ch <- pollResult{n: n, err: err}
+		}()
+		a.pending = ch
+	}
+
+	timer := time.NewTimer(a.ReadTimeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-a.pending:
+		a.pending = nil
+		return res.n, res.err
+	case <-timer.C:
+		return 0, ErrReadTimeout
+	}
+}
+
+// scan reads a whole line and parses it, so a malformed line such as "12x"
+// is consumed rather than left in the reader to be re-read.
+func (a *App) scan() (uint, error) {
+	line, err := a.r.ReadLine()
+	if err != nil {
+		return 0, err
+	}
+
+	line = strings.TrimSpace(line)
+	n, err := strconv.ParseUint(line, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidInput, line)
+	}
+	return uint(n), nil
 }
 
 func (a *App) Write(ctx context.Context, n uint) {
-	ctx, spanEnd := opentelemetry.AddSpan(ctx, "App")
+	ctx, spanEnd := opentelemetry.AddSpan(ctx, "Write")
 	defer spanEnd()
 
 	f, err := Fibonacci(ctx, n)
-	if err != nil {
-		log.Printf("Fibonacci(%d): %v\n", n, err)
-	} else {
-		log.Printf("Fibonacci(%d) = %d\n", n, f)
-	}
+	a.emit(n, f, err)
 }
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// captureLog redirects the standard logger into a buffer for the duration of a test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestRunSkipsInputAboveMaxN(t *testing.T) {
+	out := captureLog(t)
+
+	app := NewApp(strings.NewReader("5\n50\n7\n"))
+	app.MaxN = 10
+
+	if err := app.Run(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF but got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"Fibonacci(5) = 5", "Fibonacci(50): input exceeds the maximum of 10", "Fibonacci(7) = 13"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected log to contain %q, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Fibonacci(50) =") {
+		t.Error("Expected the over-limit input not to be computed")
+	}
+}
+
+func TestPollReadTimeout(t *testing.T) {
+	captureLog(t)
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	app := NewApp(pr)
+	app.ReadTimeout = 20 * time.Millisecond
+
+	start := time.Now()
+	if _, err := app.Poll(context.Background()); !errors.Is(err, ErrReadTimeout) {
+		t.Fatalf("Expected ErrReadTimeout but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Poll to time out promptly but took %v", elapsed)
+	}
+
+	// the timed out read is resumed rather than duplicated
+	go func() { _, _ = pw.Write([]byte("7\n")) }()
+	app.ReadTimeout = time.Second
+	n, err := app.Poll(context.Background())
+	if err != nil || n != 7 {
+		t.Errorf("Expected 7 from the resumed read but got %d (%v)", n, err)
+	}
+}
+
+func TestNewAppWithConfig(t *testing.T) {
+	stdlog := captureLog(t)
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	var out bytes.Buffer
+	app := NewAppWithConfig(AppConfig{
+		Input:       pr,
+		Output:      &out,
+		MaxN:        10,
+		ReadTimeout: 20 * time.Millisecond,
+	})
+
+	if _, err := app.Poll(context.Background()); !errors.Is(err, ErrReadTimeout) {
+		t.Errorf("Expected the configured ReadTimeout to apply, got %v", err)
+	}
+
+	go func() { _, _ = io.WriteString(pw, "50\n7\n") }()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_ = app.Run(ctx)
+
+	got := out.String()
+	for _, want := range []string{"Fibonacci(50): input exceeds the maximum of 10", "Fibonacci(7) = 13"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, got)
+		}
+	}
+	if stdlog.Len() != 0 {
+		t.Errorf("Expected nothing on the standard logger, got %s", stdlog.String())
+	}
+
+	if app := NewApp(strings.NewReader("")); app.MaxN != DefaultMaxN || app.ReadTimeout != 0 {
+		t.Errorf("Expected NewApp to use the defaults, got MaxN %d and ReadTimeout %v", app.MaxN, app.ReadTimeout)
+	}
+}
+
+func TestRunStopsAfterMaxInputs(t *testing.T) {
+	var out bytes.Buffer
+	app := NewAppWithConfig(AppConfig{Input: strings.NewReader("1\n2\n3\n4\n5\n"), Output: &out, MaxInputs: 3})
+
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatalf("Expected Run to return nil after MaxInputs but got %v", err)
+	}
+
+	got := out.String()
+	for _, n := range []string{"Fibonacci(1)", "Fibonacci(2)", "Fibonacci(3)"} {
+		if !strings.Contains(got, n) {
+			t.Errorf("Expected %s to be processed, got %s", n, got)
+		}
+	}
+	for _, n := range []string{"Fibonacci(4)", "Fibonacci(5)"} {
+		if strings.Contains(got, n) {
+			t.Errorf("Expected %s not to be processed, got %s", n, got)
+		}
+	}
+
+	if n, err := app.Poll(context.Background()); err != nil || n != 4 {
+		t.Errorf("Expected the fourth value to be left unread, got %d (%v)", n, err)
+	}
+}
+
+func TestWriteSpanIsChildOfPoll(t *testing.T) {
+	captureLog(t)
+
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	app := NewAppWithConfig(AppConfig{Input: strings.NewReader("5\n7\n"), Output: io.Discard, MaxInputs: 2})
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	var polls, writes []sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		switch s.Name() {
+		case "Poll":
+			polls = append(polls, s)
+		case "Write":
+			writes = append(writes, s)
+		}
+	}
+	if len(polls) != 2 || len(writes) != 2 {
+		t.Fatalf("Expected 2 Poll and 2 Write spans but got %d and %d", len(polls), len(writes))
+	}
+
+	for i, w := range writes {
+		if w.Parent().SpanID() != polls[i].SpanContext().SpanID() {
+			t.Errorf("Expected Write span %d to be a child of Poll span %d", i, i)
+		}
+	}
+}
+
+func TestRunPrompt(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"n> ", "n> 5\nFibonacci(5) = 5\nn> 7\nFibonacci(7) = 13\nn> "},
+		{"", "Fibonacci(5) = 5\nFibonacci(7) = 13\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		app := NewAppWithConfig(AppConfig{Input: strings.NewReader("5\n7\n"), Output: &out, Prompt: tt.prompt})
+		_ = app.Run(context.Background())
+
+		got := strings.ReplaceAll(out.String(), "This what Fabicca would like to know\n", "")
+		if got != tt.want {
+			t.Errorf("Prompt %q: expected output %q but got %q", tt.prompt, tt.want, got)
+		}
+	}
+}
+
+func TestRunNDJSON(t *testing.T) {
+	captureLog(t)
+
+	var out bytes.Buffer
+	app := NewAppWithConfig(AppConfig{Input: strings.NewReader("0\n7\n100\n20\n"), Output: &out, Format: OutputNDJSON, MaxN: 50})
+	if err := app.Run(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF but got %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"n": 0.0, "result": 0.0},
+		{"n": 7.0, "result": 13.0},
+		{"n": 100.0, "error": "input exceeds the maximum of 50, skipping"},
+		{"n": 20.0, "result": 6765.0},
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines but got %d: %q", len(want), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Errorf("Line %d is not valid JSON: %q (%v)", i, line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("Line %d: expected %v but got %v", i, want[i], got)
+		}
+	}
+}
+
+func TestRunSkipsMalformedLine(t *testing.T) {
+	var out bytes.Buffer
+	app := NewAppWithConfig(AppConfig{Input: strings.NewReader("12x\n\n7\n-3\n8"), Output: &out})
+
+	if err := app.Run(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF but got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{`invalid input "12x", skipping`, `invalid input "", skipping`, "Fibonacci(7) = 13", `invalid input "-3", skipping`, "Fibonacci(8) = 21"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, got)
+		}
+	}
+}
+
+func TestRunMultipleInputs(t *testing.T) {
+	sink := &SliceSink{}
+	app := NewAppWithConfig(AppConfig{
+		Input:  strings.NewReader("5\n6"),
+		Inputs: []io.Reader{strings.NewReader("7\n"), strings.NewReader(""), strings.NewReader("8\n")},
+		Sink:   sink,
+	})
+
+	if err := app.Run(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF but got %v", err)
+	}
+
+	var got []uint
+	for _, e := range sink.Results() {
+		got = append(got, e.N)
+	}
+	if want := []uint{5, 6, 7, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v but got %v", want, got)
+	}
+}
+
+func TestRunMultipleInputsReadError(t *testing.T) {
+	captureLog(t)
+
+	broken := errors.New("disk on fire")
+	app := NewApp(strings.NewReader("5\n"), iotest.ErrReader(broken), strings.NewReader("7\n"))
+
+	if err := app.Run(context.Background()); !errors.Is(err, broken) {
+		t.Errorf("Expected the read error but got %v", err)
+	}
+}
+
+func TestFormatSummary(t *testing.T) {
+	tests := []struct {
+		stats    Stats
+		expected string
+	}{
+		{Stats{}, "shutdown: processed 0 values, 0 errors, uptime 0s"},
+		{Stats{Processed: 12, Errors: 2, Uptime: 90*time.Second + 400*time.Millisecond}, "shutdown: processed 12 values, 2 errors, uptime 1m30s"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSummary(tt.stats); got != tt.expected {
+			t.Errorf("Expected %q but got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestRunCountsStats(t *testing.T) {
+	captureLog(t)
+
+	app := NewApp(strings.NewReader("5\nabc\n50\n7\n"))
+	app.MaxN = 10
+	if err := app.Run(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF but got %v", err)
+	}
+
+	if s := app.Stats(); s.Processed != 3 || s.Errors != 2 {
+		t.Errorf("Expected 3 processed and 2 errors but got %+v", s)
+	}
+}
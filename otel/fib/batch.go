@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Result is the outcome of computing Fibonacci for one input.
+type Result struct {
+	N     uint
+	Value uint64
+	Err   error
+}
+
+// compute is the function batches call for each input; tests replace it.
+var compute = Fibonacci
+
+// readInputs polls until the reader is exhausted, skipping invalid lines.
+func (a *App) readInputs(ctx context.Context) ([]uint, error) {
+	var ns []uint
+	for {
+		n, err := a.Poll(ctx)
+		if errors.Is(err, io.EOF) {
+			return ns, nil
+		}
+		if errors.Is(err, ErrInvalidInput) {
+			a.log.Printf("Poll: %v, skipping\n", err)
+			continue
+		}
+		if err != nil {
+			return ns, err
+		}
+		ns = append(ns, n)
+	}
+}
+
+func (a *App) computeOne(ctx context.Context, n uint) Result {
+	if n > a.MaxN {
+		return Result{N: n, Err: fmt.Errorf("input exceeds the maximum of %d", a.MaxN)}
+	}
+	v, err := compute(ctx, n)
+	return Result{N: n, Value: v, Err: err}
+}
+
+// RunBatch reads every input until EOF, then computes them one at a time and
+// returns the results in input order.
+func (a *App) RunBatch(ctx context.Context) ([]Result, error) {
+	ns, err := a.readInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(ns))
+	for _, n := range ns {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		results = append(results, a.computeOne(ctx, n))
+	}
+	return results, nil
+}
+
+// RunBatchN is RunBatch with the computations spread over a pool of workers.
+// Results keep the input order. Cancelling ctx stops all workers; the results
+// computed so far are returned with ctx.Err().
+func (a *App) RunBatchN(ctx context.Context, workers int) ([]Result, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ns, err := a.readInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(ns))
+	done := make([]bool, len(ns))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = a.computeOne(ctx, ns[i])
+				done[i] = true
+			}
+		}()
+	}
+
+feed:
+	for i := range ns {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		partial := make([]Result, 0, len(ns))
+		for i, ok := range done {
+			if ok {
+				partial = append(partial, results[i])
+			}
+		}
+		return partial, err
+	}
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatchNMatchesRunBatch(t *testing.T) {
+	captureLog(t)
+	input := "1\n2\n3\n10\n100\n20\n30\n50\n"
+
+	serial, err := NewApp(strings.NewReader(input)).RunBatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent, err := NewApp(strings.NewReader(input)).RunBatchN(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(serial, concurrent) {
+		t.Errorf("Expected concurrent results %v to match serial %v", concurrent, serial)
+	}
+	if serial[3].Value != 55 || serial[4].Err == nil {
+		t.Errorf("Unexpected results %v", serial)
+	}
+}
+
+func TestRunBatchNHonoursWorkerCount(t *testing.T) {
+	captureLog(t)
+
+	var active, peak int32
+	compute = func(ctx context.Context, n uint) (uint64, error) {
+		cur := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return uint64(n), nil
+	}
+	defer func() { compute = Fibonacci }()
+
+	input := strings.Repeat("5\n", 12)
+	if _, err := NewApp(strings.NewReader(input)).RunBatchN(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if peak != 3 {
+		t.Errorf("Expected 3 concurrent workers but saw %d", peak)
+	}
+}
+
+func TestRunBatchNCancelled(t *testing.T) {
+	captureLog(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := NewApp(strings.NewReader("1\n2\n3\n")).RunBatchN(ctx, 2)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+	if len(results) == 3 {
+		t.Errorf("Expected cancellation to stop the batch, got %v", results)
+	}
+}
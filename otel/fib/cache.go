@@ -0,0 +1,68 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is how many results Fibonacci memoizes by default.
+const DefaultCacheSize = 64
+
+// memo caches successful Fibonacci results across calls.
+var memo = newLRU(DefaultCacheSize)
+
+// lru is a fixed-capacity cache of F(n) that evicts the least recently used
+// entry once full. A capacity of zero disables caching.
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	items    map[uint]*list.Element
+}
+
+type lruEntry struct {
+	n uint
+	f uint64
+}
+
+func newLRU(capacity int) *lru {
+	return &lru{capacity: capacity, order: list.New(), items: map[uint]*list.Element{}}
+}
+
+func (c *lru) get(n uint) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[n]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(lruEntry).f, true
+}
+
+func (c *lru) add(n uint, f uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+	if e, ok := c.items[n]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.items[n] = c.order.PushFront(lruEntry{n: n, f: f})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(lruEntry).n)
+	}
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLRU(3)
+	for n := uint(1); n <= 3; n++ {
+		c.add(n, uint64(n))
+	}
+
+	// touching 1 makes 2 the oldest
+	c.get(1)
+	c.add(4, 4)
+	c.add(5, 5)
+
+	if c.len() != 3 {
+		t.Errorf("Expected the cache to hold 3 entries but got %d", c.len())
+	}
+	for _, n := range []uint{2, 3} {
+		if _, ok := c.get(n); ok {
+			t.Errorf("Expected %d to have been evicted", n)
+		}
+	}
+	for _, n := range []uint{1, 4, 5} {
+		if f, ok := c.get(n); !ok || f != uint64(n) {
+			t.Errorf("Expected %d to be cached but got %d (%v)", n, f, ok)
+		}
+	}
+}
+
+func TestLRUZeroCapacity(t *testing.T) {
+	c := newLRU(0)
+	c.add(1, 1)
+	if _, ok := c.get(1); ok {
+		t.Error("Expected a zero-capacity cache to store nothing")
+	}
+}
+
+func TestFibonacciUsesCache(t *testing.T) {
+	prev := memo
+	memo = newLRU(2)
+	t.Cleanup(func() { memo = prev })
+
+	for _, n := range []uint{10, 20, 30} {
+		if _, err := Fibonacci(context.Background(), n); err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+	}
+
+	if _, ok := memo.get(10); ok {
+		t.Error("Expected F(10) to have been evicted")
+	}
+	if f, ok := memo.get(30); !ok || f != 832040 {
+		t.Errorf("Expected F(30) = 832040 to be cached but got %d (%v)", f, ok)
+	}
+}
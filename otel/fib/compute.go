@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// computeLimit bounds how many values ComputeMany computes at once.
+var computeLimit = runtime.NumCPU()
+
+// ComputeMany computes Fibonacci for every n concurrently, at most
+// computeLimit at a time, and returns the values in input order. The first
+// error, such as an overflow, cancels the remaining computations and is
+// returned with a nil slice.
+func ComputeMany(ctx context.Context, ns []uint) ([]uint64, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(computeLimit)
+
+	values := make([]uint64, len(ns))
+	for i, n := range ns {
+		i, n := i, n
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			v, err := compute(ctx, n)
+			if err != nil {
+				return fmt.Errorf("Fibonacci(%d): %w", n, err)
+			}
+			values[i] = v
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
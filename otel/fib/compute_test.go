@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestComputeMany(t *testing.T) {
+	got, err := ComputeMany(context.Background(), []uint{10, 1, 50, 0, 93})
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	want := []uint64{55, 1, 12586269025, 0, 12200160415121876738}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v but got %v", want, got)
+	}
+}
+
+func TestComputeManyCancelsOnError(t *testing.T) {
+	var started int32
+	compute = func(ctx context.Context, n uint) (uint64, error) {
+		atomic.AddInt32(&started, 1)
+		if n > maxN {
+			return 0, ErrOverflow
+		}
+		select {
+		case <-time.After(time.Second):
+			return uint64(n), nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	defer func() { compute = Fibonacci }()
+
+	limit := computeLimit
+	computeLimit = 2
+	defer func() { computeLimit = limit }()
+
+	ns := []uint{94, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	start := time.Now()
+	got, err := ComputeMany(context.Background(), ns)
+
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow but got %v", err)
+	}
+	if got != nil {
+		t.Errorf("Expected no values on error but got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the group to be cancelled promptly but took %v", elapsed)
+	}
+	if n := atomic.LoadInt32(&started); n == int32(len(ns)) {
+		t.Errorf("Expected the remaining inputs to be skipped, but all %d started", n)
+	}
+}
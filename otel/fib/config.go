@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anzx/pkg/opentelemetry"
+	"github.com/anzx/pkg/opentelemetry/exporters"
+	"github.com/anzx/pkg/opentelemetry/metrics"
+	"github.com/anzx/pkg/opentelemetry/trace"
+)
+
+// fileConfig is the JSON shape read by -config. It mirrors opentelemetry.Config
+// but only carries the fields this app sets, e.g.
+//
+//	{
+//	  "metrics": {"exporter": "prometheus"},
+//	  "trace": {"exporter": "jaeger"},
+//	  "exporters": {"jaeger": {"collectorEndpoint": "http://jaeger:14268/api/traces"}}
+//	}
+type fileConfig struct {
+	Metrics struct {
+		Exporter string `json:"exporter"`
+	} `json:"metrics"`
+	Trace struct {
+		Exporter string `json:"exporter"`
+	} `json:"trace"`
+	Exporters struct {
+		Jaeger struct {
+			CollectorEndpoint string `json:"collectorEndpoint"`
+		} `json:"jaeger"`
+	} `json:"exporters"`
+}
+
+var (
+	knownMetricsExporters = map[string]bool{"prometheus": true, "stdout": true}
+	knownTraceExporters   = map[string]bool{"jaeger": true, "stdout": true}
+)
+
+// loadConfig reads and validates an otel config file.
+func loadConfig(path string) (*opentelemetry.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fc fileConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("%s: malformed config: %w", path, err)
+	}
+
+	if err := fc.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg := &opentelemetry.Config{
+		Metrics: metrics.Config{Exporter: fc.Metrics.Exporter},
+		Trace:   trace.Config{Exporter: fc.Trace.Exporter},
+	}
+	if fc.Trace.Exporter == "jaeger" {
+		cfg.Exporters.Jaeger = exporters.JaegerConfig{CollectorEndpoint: fc.Exporters.Jaeger.CollectorEndpoint}
+	} else {
+		cfg.Exporters.Stdout = exporters.StdoutConfig{}
+	}
+	return cfg, nil
+}
+
+func (fc fileConfig) validate() error {
+	switch {
+	case fc.Metrics.Exporter == "":
+		return errors.New("metrics.exporter is required")
+	case !knownMetricsExporters[fc.Metrics.Exporter]:
+		return fmt.Errorf("unknown metrics exporter %q", fc.Metrics.Exporter)
+	case fc.Trace.Exporter == "":
+		return errors.New("trace.exporter is required")
+	case !knownTraceExporters[fc.Trace.Exporter]:
+		return fmt.Errorf("unknown trace exporter %q", fc.Trace.Exporter)
+	case fc.Trace.Exporter == "jaeger" && fc.Exporters.Jaeger.CollectorEndpoint == "":
+		return errors.New("exporters.jaeger.collectorEndpoint is required for the jaeger exporter")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "otel.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"metrics": {"exporter": "prometheus"},
+		"trace": {"exporter": "jaeger"},
+		"exporters": {"jaeger": {"collectorEndpoint": "http://jaeger:14268/api/traces"}}
+	}`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if cfg.Metrics.Exporter != "prometheus" || cfg.Trace.Exporter != "jaeger" {
+		t.Errorf("Unexpected exporters %+v", cfg)
+	}
+	if cfg.Exporters.Jaeger.CollectorEndpoint != "http://jaeger:14268/api/traces" {
+		t.Errorf("Unexpected jaeger endpoint %q", cfg.Exporters.Jaeger.CollectorEndpoint)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{"malformed", `{"metrics": `, "malformed"},
+		{"unknown-field", `{"metrics": {"exporter": "stdout"}, "bogus": 1}`, "malformed"},
+		{"missing-trace", `{"metrics": {"exporter": "stdout"}}`, "trace.exporter is required"},
+		{"unknown-exporter", `{"metrics": {"exporter": "stdout"}, "trace": {"exporter": "zipkin"}}`, `unknown trace exporter "zipkin"`},
+		{"jaeger-without-endpoint", `{"metrics": {"exporter": "stdout"}, "trace": {"exporter": "jaeger"}}`, "collectorEndpoint is required"},
+	}
+
+	for _, tt := range tests {
+		_, err := loadConfig(writeConfig(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.errText) {
+			t.Errorf("%s: expected an error containing %q but got %v", tt.name, tt.errText, err)
+		}
+	}
+}
@@ -2,26 +2,148 @@ package main
 
 import (
 	"context"
+	"errors"
+	"math/big"
 	"time"
 
 	"github.com/anzx/pkg/opentelemetry"
-	
 )
 
+// SimulatedLatency is slept at the start of every Fibonacci call. It is zero by
+// default; set it to mimic a slow backend when demoing traces.
+var SimulatedLatency time.Duration
+
+// maxN is the largest n whose Fibonacci number fits in a uint64.
+const maxN = 93
+
+// ErrOverflow is returned by Fibonacci when the result does not fit in a uint64.
+var ErrOverflow = errors.New("result overflows uint64")
+
 func Fibonacci(ctx context.Context, n uint) (uint64, error) {
 	_, spanEnd := opentelemetry.AddSpan(ctx, "Main")
-	time.Sleep(time.Microsecond * 500)
-
 	defer spanEnd()
 
-	if n <= 1 {
-		return uint64(n), nil
+	f, err := memoFibonacci(ctx, n)
+	fibStats.record(err)
+	return f, err
+}
+
+// memoFibonacci is fibonacci backed by the memo cache. A cancelled call fails
+// even if its result is cached.
+func memoFibonacci(ctx context.Context, n uint) (uint64, error) {
+	if ctx.Err() == nil {
+		if f, ok := memo.get(n); ok {
+			return f, nil
+		}
+	}
+
+	f, err := fibonacci(ctx, n)
+	if err == nil {
+		memo.add(n, f)
+	}
+	return f, err
+}
+
+// fibonacci computes F(n), giving up early if ctx is done
+func fibonacci(ctx context.Context, n uint) (uint64, error) {
+	if SimulatedLatency > 0 {
+		timer := time.NewTimer(SimulatedLatency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if n < uint(len(fibTable)) {
+		return fibTable[n], nil
+	}
+
+	if n > maxN {
+		return 0, ErrOverflow
 	}
+	return bigFibonacci(n).Uint64(), nil
+}
+
+// fibTable holds F(0) to F(92), so most calls are a single array lookup.
+var fibTable = func() (t [93]uint64) {
+	t[1] = 1
+	for i := 2; i < len(t); i++ {
+		t[i] = t[i-1] + t[i-2]
+	}
+	return t
+}()
+
+// bigFibonacci computes F(n) for n past the end of fibTable; tests replace it.
+var bigFibonacci = fibonacciBig
 
-	var n2, n1 uint64 = 0, 1
-	for i := uint(2); i < n; i++ {
-		n2, n1 = n1, n1+n2
+func fibonacciBig(n uint) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint(0); i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+// FibonacciBig returns F(n) for any n, including those past the uint64 range
+// of Fibonacci.
+func FibonacciBig(ctx context.Context, n uint) (*big.Int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if n < uint(len(fibTable)) {
+		return new(big.Int).SetUint64(fibTable[n]), nil
 	}
+	return bigFibonacci(n), nil
+}
+
+// FibonacciIndex returns n such that F(n) == v, and false if v is not a
+// Fibonacci number. For v == 1, which is both F(1) and F(2), it returns 1. The
+// sequence is walked up to v, which takes at most maxN steps.
+func FibonacciIndex(v uint64) (uint, bool) {
+	var a, b uint64 = 0, 1
+	for n := uint(0); n <= maxN; n++ {
+		if a == v {
+			return n, true
+		}
+		if a > v {
+			break
+		}
+		a, b = b, a+b
+	}
+	return 0, false
+}
+
+// FibonacciStream sends successive Fibonacci numbers, starting at F(0), until
+// ctx is cancelled or the next value would overflow a uint64, then closes the
+// channel. Cancelling ctx is enough to release the sending goroutine.
+func FibonacciStream(ctx context.Context) <-chan uint64 {
+	ch := make(chan uint64)
+
+	go func() {
+		defer close(ch)
+
+		var a, b uint64 = 0, 1
+		for {
+			select {
+			case ch <- a:
+			case <-ctx.Done():
+				return
+			}
+
+			if b < a {
+				return
+			}
+			a, b = b, a+b
+		}
+	}()
 
-	return n2 + n1, nil
+	return ch
 }
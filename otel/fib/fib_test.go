@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFibonacci(t *testing.T) {
+	tests := []struct {
+		n    uint
+		want uint64
+	}{
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{10, 55},
+		{50, 12586269025},
+		{93, 12200160415121876738},
+	}
+
+	for _, tt := range tests {
+		got, err := Fibonacci(context.Background(), tt.n)
+		if err != nil {
+			t.Errorf("Fibonacci(%d): unexpected error %v", tt.n, err)
+		}
+		if got != tt.want {
+			t.Errorf("Fibonacci(%d): expected %d but got %d", tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestFibonacciOverflow(t *testing.T) {
+	if _, err := Fibonacci(context.Background(), 94); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow but got %v", err)
+	}
+}
+
+func BenchmarkFibonacci(b *testing.B) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		_, _ = Fibonacci(ctx, 90)
+	}
+}
+
+func TestFibonacciStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := FibonacciStream(ctx)
+	want := []uint64{0, 1, 1, 2, 3, 5, 8, 13, 21, 34}
+	for i, w := range want {
+		if got := <-ch; got != w {
+			t.Errorf("value %d: expected %d but got %d", i, w, got)
+		}
+	}
+
+	cancel()
+
+	// values already in flight may still arrive, but the channel must close
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for the channel to close")
+		}
+	}
+}
+
+func TestFibonacciStreamStopsBeforeOverflow(t *testing.T) {
+	var count int
+	var last uint64
+	for v := range FibonacciStream(context.Background()) {
+		if v < last {
+			t.Fatalf("Value %d overflowed after %d", v, last)
+		}
+		last = v
+		count++
+	}
+
+	if count != 94 {
+		t.Errorf("Expected F(0)..F(93) (94 values) but got %d", count)
+	}
+}
+
+func TestFibonacciIndex(t *testing.T) {
+	tests := []struct {
+		v     uint64
+		n     uint
+		isFib bool
+	}{
+		{0, 0, true},
+		{1, 1, true},
+		{2, 3, true},
+		{55, 10, true},
+		{832040, 30, true},
+		{12200160415121876738, 93, true},
+		{4, 0, false},
+		{56, 0, false},
+		{832041, 0, false},
+		{math.MaxUint64, 0, false},
+	}
+
+	for _, tt := range tests {
+		n, ok := FibonacciIndex(tt.v)
+		if ok != tt.isFib || n != tt.n {
+			t.Errorf("FibonacciIndex(%d): expected (%d, %v) but got (%d, %v)", tt.v, tt.n, tt.isFib, n, ok)
+		}
+	}
+}
+
+func TestFibonacciTable(t *testing.T) {
+	for n := range fibTable {
+		want := fibonacciBig(uint(n))
+		if !want.IsUint64() || fibTable[n] != want.Uint64() {
+			t.Errorf("fibTable[%d] = %d, expected %s", n, fibTable[n], want)
+		}
+	}
+}
+
+func TestFibonacciBigPath(t *testing.T) {
+	var calls []uint
+	bigFibonacci = func(n uint) *big.Int {
+		calls = append(calls, n)
+		return fibonacciBig(n)
+	}
+	defer func() { bigFibonacci = fibonacciBig }()
+
+	for _, n := range []uint{0, 50, 92} {
+		if _, err := fibonacci(context.Background(), n); err != nil {
+			t.Errorf("F(%d): got an error when should not have: %v", n, err)
+		}
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected n <= 92 to come from the table, but the big path computed %v", calls)
+	}
+
+	if f, err := fibonacci(context.Background(), 93); err != nil || f != 12200160415121876738 {
+		t.Errorf("Expected F(93) = 12200160415121876738 but got %d (%v)", f, err)
+	}
+	if _, err := fibonacci(context.Background(), 94); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow for F(94) but got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []uint{93}) {
+		t.Errorf("Expected only F(93) to take the big path but got %v", calls)
+	}
+
+	f, err := FibonacciBig(context.Background(), 100)
+	if want := "354224848179261915075"; err != nil || f.String() != want {
+		t.Errorf("Expected F(100) = %s but got %v (%v)", want, f, err)
+	}
+}
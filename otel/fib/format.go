@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Separator is inserted between groups of three digits by FormatResult. Set it
+// to suit the locale, e.g. "." or " "; an empty string disables grouping.
+var Separator = ","
+
+// FormatResult formats v with its digits grouped in threes, e.g. 12,586,269,025.
+func FormatResult(v uint64) string {
+	return groupDigits(strconv.FormatUint(v, 10))
+}
+
+// FormatBigResult is FormatResult for values that don't fit in a uint64.
+func FormatBigResult(v *big.Int) string {
+	s := v.String()
+	if strings.HasPrefix(s, "-") {
+		return "-" + groupDigits(s[1:])
+	}
+	return groupDigits(s)
+}
+
+// groupDigits inserts Separator into a string of decimal digits.
+func groupDigits(digits string) string {
+	if Separator == "" || len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead == 0 {
+		lead = 3
+	}
+	b.WriteString(digits[:lead])
+	for i := lead; i < len(digits); i += 3 {
+		b.WriteString(Separator)
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestFormatResult(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		sep  string
+		want string
+	}{
+		{0, ",", "0"},
+		{55, ",", "55"},
+		{999, ",", "999"},
+		{1000, ",", "1,000"},
+		{832040, ",", "832,040"},
+		{12586269025, ",", "12,586,269,025"},
+		{12586269025, ".", "12.586.269.025"},
+		{12586269025, "", "12586269025"},
+	}
+
+	defer func() { Separator = "," }()
+	for _, tt := range tests {
+		Separator = tt.sep
+		if got := FormatResult(tt.v); got != tt.want {
+			t.Errorf("FormatResult(%d) with %q: expected %s but got %s", tt.v, tt.sep, tt.want, got)
+		}
+	}
+}
+
+func TestFormatBigResult(t *testing.T) {
+	v, _ := new(big.Int).SetString("354224848179261915075", 10)
+
+	tests := []struct {
+		v    *big.Int
+		want string
+	}{
+		{big.NewInt(0), "0"},
+		{v, "354,224,848,179,261,915,075"},
+		{big.NewInt(-1234567), "-1,234,567"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBigResult(tt.v); got != tt.want {
+			t.Errorf("FormatBigResult(%s): expected %s but got %s", tt.v, tt.want, got)
+		}
+	}
+}
+
+func TestWriteGroupsDigits(t *testing.T) {
+	var out strings.Builder
+	app := NewAppWithConfig(AppConfig{Output: &out})
+	app.Write(context.Background(), 50)
+
+	if want := "Fibonacci(50) = 12,586,269,025"; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q in %q", want, out.String())
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// fibResult is the body returned by the HTTP handlers, shared by the JSON and
+// XML encoders.
+type fibResult struct {
+	XMLName xml.Name `json:"-" xml:"fibResult"`
+	N       uint     `json:"n" xml:"n"`
+	Result  uint64   `json:"result" xml:"result"`
+}
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeXML  = "application/xml"
+)
+
+// negotiate picks the response content type from an Accept header. JSON is
+// the default; ok is false when none of the accepted types can be produced.
+func negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return contentTypeJSON, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case contentTypeJSON, "application/*", "*/*":
+			return contentTypeJSON, true
+		case contentTypeXML, "text/xml":
+			return contentTypeXML, true
+		}
+	}
+	return "", false
+}
+
+// fibHandler serves GET /fib?n=<n>.
+func fibHandler(w http.ResponseWriter, r *http.Request) {
+	contentType, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "supported types are application/json and application/xml", http.StatusNotAcceptable)
+		return
+	}
+
+	n, err := strconv.ParseUint(r.URL.Query().Get("n"), 10, 0)
+	if err != nil {
+		http.Error(w, "n must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	f, err := Fibonacci(r.Context(), uint(n))
+	if errors.Is(err, ErrOverflow) {
+		// the client asked for an n too large, not a server fault
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeResult(w, contentType, fibResult{N: uint(n), Result: f})
+}
+
+// fibIndexHandler serves GET /fib/index?v=<v>, the inverse of /fib: it
+// returns the n for which F(n) is v, or 400 if v is not a Fibonacci number.
+func fibIndexHandler(w http.ResponseWriter, r *http.Request) {
+	contentType, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "supported types are application/json and application/xml", http.StatusNotAcceptable)
+		return
+	}
+
+	v, err := strconv.ParseUint(r.URL.Query().Get("v"), 10, 64)
+	if err != nil {
+		http.Error(w, "v must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	n, ok := FibonacciIndex(v)
+	if !ok {
+		http.Error(w, "v is not a Fibonacci number", http.StatusBadRequest)
+		return
+	}
+
+	writeResult(w, contentType, fibResult{N: n, Result: v})
+}
+
+func writeResult(w http.ResponseWriter, contentType string, res fibResult) {
+	w.Header().Set("Content-Type", contentType)
+	if contentType == contentTypeXML {
+		_, _ = w.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(w).Encode(res)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// batchResult is one entry of a /fib/batch response.
+type batchResult struct {
+	N      uint   `json:"n"`
+	Result uint64 `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DefaultMaxBatch is the default limit on the number of values in one batch.
+const DefaultMaxBatch = 1000
+
+// batchValueBytes is the body size allowed per batch value: 20 digits for the
+// largest uint64, a comma and room for whitespace.
+const batchValueBytes = 32
+
+// fibBatchHandler serves POST /fib/batch, taking a JSON array of n values and
+// returning a result per value. Work stops as soon as the request context is
+// cancelled, e.g. when the client disconnects.
+func fibBatchHandler(maxBatch int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// cap the body before decoding, so an oversized batch is turned away
+		// without being read into memory first
+		r.Body = http.MaxBytesReader(w, r.Body, int64(maxBatch+1)*batchValueBytes)
+
+		var ns []uint
+		if err := json.NewDecoder(r.Body).Decode(&ns); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "batch exceeds "+strconv.Itoa(maxBatch)+" values", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "body must be a JSON array of non-negative integers", http.StatusBadRequest)
+			return
+		}
+		if len(ns) > maxBatch {
+			http.Error(w, "batch exceeds "+strconv.Itoa(maxBatch)+" values", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		ctx := r.Context()
+		results := make([]batchResult, 0, len(ns))
+		for _, n := range ns {
+			if ctx.Err() != nil {
+				return
+			}
+
+			res := batchResult{N: n}
+			f, err := Fibonacci(ctx, n)
+			if err != nil {
+				res.Error = err.Error()
+			} else {
+				res.Result = f
+			}
+			results = append(results, res)
+		}
+
+		w.Header().Set("Content-Type", contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(results)
+	}
+}
+
+// fibStreamHandler serves GET /fib/stream as server-sent events, one per
+// Fibonacci number starting at F(0), with the index as the event ID. The
+// stream ends when the client goes away or the next value would overflow.
+func fibStreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	n := 0
+	for f := range FibonacciStream(r.Context()) {
+		if _, err := fmt.Fprintf(w, "id: %d\ndata: %d\n\n", n, f); err != nil {
+			return
+		}
+		flusher.Flush()
+		n++
+	}
+}
+
+// newServeMux returns the routes served by the -http listener.
+func newServeMux(maxBatch int) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fib", fibHandler)
+	mux.HandleFunc("/fib/batch", fibBatchHandler(maxBatch))
+	mux.HandleFunc("/fib/index", fibIndexHandler)
+	mux.HandleFunc("/fib/stream", fibStreamHandler)
+	return mux
+}
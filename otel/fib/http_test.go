@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFibHandlerContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"default", "", http.StatusOK, contentTypeJSON, `{"n":10,"result":55}`},
+		{"json", "application/json", http.StatusOK, contentTypeJSON, `{"n":10,"result":55}`},
+		{"xml", "application/xml", http.StatusOK, contentTypeXML, "<fibResult><n>10</n><result>55</result></fibResult>"},
+		{"xml-with-params", "text/html, application/xml;q=0.9", http.StatusOK, contentTypeXML, "<result>55</result>"},
+		{"unsupported", "text/html", http.StatusNotAcceptable, "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/fib?n=10", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		rr := httptest.NewRecorder()
+		fibHandler(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+			continue
+		}
+		if tt.contentType != "" && rr.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: expected content type %s but got %s", tt.name, tt.contentType, rr.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rr.Body.String(), tt.body) {
+			t.Errorf("%s: expected body to contain %q, got %q", tt.name, tt.body, rr.Body.String())
+		}
+	}
+}
+
+func TestFibHandlerBadN(t *testing.T) {
+	rr := httptest.NewRecorder()
+	fibHandler(rr, httptest.NewRequest(http.MethodGet, "/fib?n=abc", nil))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d but got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestFibHandlerOverflow(t *testing.T) {
+	rr := httptest.NewRecorder()
+	fibHandler(rr, httptest.NewRequest(http.MethodGet, "/fib?n=100", nil))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d but got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), ErrOverflow.Error()) {
+		t.Errorf("Expected the overflow error in the body, got %q", rr.Body.String())
+	}
+}
+
+func TestFibBatchHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"normal", `[0, 1, 10]`, http.StatusOK, `[{"n":0,"result":0},{"n":1,"result":1},{"n":10,"result":55}]`},
+		{"overflow", `[10, 100]`, http.StatusOK, `[{"n":10,"result":55},{"n":100,"result":0,"error":"result overflows uint64"}]`},
+		{"over-limit", `[1, 2, 3, 4]`, http.StatusRequestEntityTooLarge, ""},
+		{"oversized-body", "[" + strings.Repeat("1,", 1000) + "1]", http.StatusRequestEntityTooLarge, ""},
+		{"padded", "[\n    18446744073709551615,\n    1,\n    2\n]", http.StatusOK, ""},
+		{"bad-body", `{"n": 1}`, http.StatusBadRequest, ""},
+	}
+
+	h := fibBatchHandler(3)
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		h(rr, httptest.NewRequest(http.MethodPost, "/fib/batch", strings.NewReader(tt.body)))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, rr.Code)
+			continue
+		}
+		if tt.want != "" && strings.TrimSpace(rr.Body.String()) != tt.want {
+			t.Errorf("%s: expected %s but got %s", tt.name, tt.want, rr.Body.String())
+		}
+	}
+}
+
+func TestFibBatchHandlerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/fib/batch", strings.NewReader(`[1, 2, 3]`)).WithContext(ctx)
+	fibBatchHandler(10)(rr, req)
+
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected no results after cancellation, got %s", rr.Body.String())
+	}
+}
+
+func TestFibIndexHandler(t *testing.T) {
+	tests := []struct {
+		query  string
+		status int
+		body   string
+	}{
+		{"?v=55", http.StatusOK, `{"n":10,"result":55}`},
+		{"?v=56", http.StatusBadRequest, "not a Fibonacci number"},
+		{"?v=abc", http.StatusBadRequest, "non-negative integer"},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		fibIndexHandler(rr, httptest.NewRequest(http.MethodGet, "/fib/index"+tt.query, nil))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.query, tt.status, rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), tt.body) {
+			t.Errorf("%s: expected body to contain %q, got %q", tt.query, tt.body, rr.Body.String())
+		}
+	}
+}
+
+func TestFibStreamHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(fibStreamHandler))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/fib/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected content type text/event-stream but got %s", ct)
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(data) < 6 && scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			data = append(data, v)
+		}
+	}
+	if want := []string{"0", "1", "1", "2", "3", "5"}; !reflect.DeepEqual(data, want) {
+		t.Errorf("Expected events %v but got %v", want, data)
+	}
+
+	// cancelling the request ends the stream
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected the stream to end after the client cancelled")
+	}
+}
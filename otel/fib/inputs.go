@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"io"
+)
+
+// lineSources reads lines from several readers in turn, moving on to the
+// next when one reaches EOF. Each reader is read a line at a time, so a final
+// line without a newline is never joined to the first line of the next one.
+type lineSources struct {
+	rs []*bufio.Reader
+}
+
+func newLineSources(rs ...io.Reader) *lineSources {
+	s := &lineSources{}
+	for _, r := range rs {
+		if r != nil {
+			s.rs = append(s.rs, bufio.NewReader(r))
+		}
+	}
+	return s
+}
+
+// ReadLine returns the next line, including its newline if it had one. It
+// returns io.EOF once every reader is exhausted; any other error is returned
+// as is and leaves the failing reader current.
+func (s *lineSources) ReadLine() (string, error) {
+	for len(s.rs) > 0 {
+		line, err := s.rs[0].ReadString('\n')
+		if err == io.EOF {
+			s.rs = s.rs[1:]
+			if line == "" {
+				continue
+			}
+			return line, nil
+		}
+		return line, err
+	}
+	return "", io.EOF
+}
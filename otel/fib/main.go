@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 
@@ -11,15 +15,93 @@ import (
 	"github.com/anzx/pkg/opentelemetry/exporters"
 	"github.com/anzx/pkg/opentelemetry/metrics"
 	"github.com/anzx/pkg/opentelemetry/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric/noop"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// stdin is where run reads values after any files; tests replace it.
+var stdin io.Reader = os.Stdin
+
+// Build information, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "dev"
+	buildDate = "dev"
 )
 
 func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// startTelemetry starts opentelemetry with cfg. Telemetry is optional: if it
+// cannot be started a warning is logged, no-op tracer and meter providers are
+// installed and false is returned, so Fibonacci keeps working without it.
+func startTelemetry(ctx context.Context, cfg *opentelemetry.Config) bool {
+	if err := opentelemetry.Start(ctx, cfg); err != nil {
+		log.Printf("warning: error starting opentelemetry, continuing without telemetry: %s", err)
+		otel.SetTracerProvider(oteltrace.NewNoopTracerProvider())
+		otel.SetMeterProvider(noop.NewMeterProvider())
+		return false
+	}
+	return true
+}
+
+// printVersion writes the build information to w
+func printVersion(w io.Writer) {
+	fmt.Fprintf(w, "fib %s (commit %s, built %s)\n", version, commit, buildDate)
+}
+
+// run parses args and runs the app until interrupted. The version subcommand
+// and -version flag print the build information and return before any otel
+// setup.
+func run(args []string, stdout io.Writer) error {
+	if len(args) > 0 && args[0] == "version" {
+		printVersion(stdout)
+		return nil
+	}
+
+	flags := flag.NewFlagSet("fib", flag.ContinueOnError)
+	httpAddr := flags.String("http", "", "serve Fibonacci over HTTP on this address, e.g. :8080")
+	maxBatch := flags.Int("max-batch", DefaultMaxBatch, "maximum number of values accepted by /fib/batch")
+	configPath := flags.String("config", "", "path to a JSON otel config, used instead of the env-based defaults")
+	showVersion := flags.Bool("version", false, "print the version and exit")
+	maxInputs := flags.Int("max-inputs", 0, "stop after reading this many values from stdin; 0 means no limit")
+	prompt := flags.String("prompt", DefaultPrompt, "prompt shown before each value when running interactively")
+	interactive := flags.Bool("interactive", isTerminal(os.Stdin), "show the prompt and echo each value; defaults to true when stdin is a terminal")
+	output := flags.String("output", string(OutputText), "result format for stdin input: text or ndjson")
+	cacheSize := flags.Int("cache-size", DefaultCacheSize, "number of results to memoize; 0 disables the cache")
+	flags.StringVar(&Separator, "separator", Separator, "digit group separator for results, e.g. \".\" or \" \"; empty disables grouping")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	memo = newLRU(*cacheSize)
+	if f := OutputFormat(*output); f != OutputText && f != OutputNDJSON {
+		return fmt.Errorf("unknown -output %q, want text or ndjson", *output)
+	}
+
+	if *showVersion {
+		printVersion(stdout)
+		return nil
+	}
+
 	var otelConfig *opentelemetry.Config
 
 	// Set exporter apprilately depending on whether or not we detect the presence
 	// of the OTEL_EXPORTER_OTLP_ENDPOINT environment variable (as per usptream OTEL docs).
+	// An explicit -config file takes precedence over both.
 
-	if endpoint, found := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); found {
+	if *configPath != "" {
+		var err error
+		otelConfig, err = loadConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+	} else if endpoint, found := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); found {
 		otelConfig = &opentelemetry.Config{
 			Metrics: metrics.Config{
 				Exporter: "prometheus",
@@ -53,23 +135,69 @@ func main() {
 
 	fmt.Println("OtelConfig:", otelConfig)
 
-	err := opentelemetry.Start(ctx, otelConfig)
-	if err != nil {
-		log.Fatalf("error starting opentelemetrty")
-	} else {
+	telemetry := startTelemetry(ctx, otelConfig)
+	if telemetry {
 		fmt.Println("Connection to Jeager Success")
 
 	}
 
+	if telemetry && otelConfig.Trace.Exporter == "stdout" {
+		pretty := os.Getenv(envStdoutPretty) == "true"
+		w, closeWriter, err := stdoutTraceWriter(os.Getenv(envStdoutTarget), pretty)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", envStdoutTarget, err)
+		}
+		defer closeWriter()
+
+		if w != nil {
+			shutdown, err := useStdoutTracer(w, pretty)
+			if err != nil {
+				return fmt.Errorf("error creating stdout exporter: %w", err)
+			}
+			defer shutdown(context.Background())
+		}
+	}
+
+	usePropagator()
+
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
-	app := NewApp(os.Stdin)
+	// files named after the flags are read in order, then stdin
+	var inputs []io.Reader
+	for _, path := range flags.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error opening input: %w", err)
+		}
+		defer f.Close()
+		inputs = append(inputs, f)
+	}
+
+	cfg := AppConfig{Inputs: append(inputs, stdin), MaxInputs: *maxInputs, Format: OutputFormat(*output)}
+	if *interactive {
+		cfg.Prompt = *prompt
+	}
+	app := NewAppWithConfig(cfg)
 	go func() {
-		if err := app.Run(ctx); err != nil {
+		// running out of input, reaching -max-inputs and being interrupted
+		// are all normal ends, and leave the summary below to be logged
+		err := app.Run(ctx)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
 			log.Fatalf("error running app: %s", err)
 		}
+		cancel()
 	}()
 
+	if *httpAddr != "" {
+		go func() {
+			if err := http.ListenAndServe(*httpAddr, withTraceContext(withRequestID(newServeMux(*maxBatch)))); err != nil {
+				log.Fatalf("error serving http: %s", err)
+			}
+		}()
+	}
+
 	<-ctx.Done()
+	log.Print(formatSummary(app.Stats()))
+	return nil
 }
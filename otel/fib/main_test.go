@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anzx/pkg/opentelemetry"
+	"github.com/anzx/pkg/opentelemetry/trace"
+)
+
+func TestRunVersion(t *testing.T) {
+	version, commit, buildDate = "1.2.3", "abc123", "2024-01-02"
+	defer func() { version, commit, buildDate = "dev", "dev", "dev" }()
+
+	want := "fib 1.2.3 (commit abc123, built 2024-01-02)\n"
+	for _, args := range [][]string{{"version"}, {"-version"}, {"--version"}} {
+		var out strings.Builder
+		if err := run(args, &out); err != nil {
+			t.Errorf("%v: got an error when should not have: %v", args, err)
+		}
+		if out.String() != want {
+			t.Errorf("%v: expected %q but got %q", args, want, out.String())
+		}
+	}
+}
+
+func TestRunBadFlag(t *testing.T) {
+	var out strings.Builder
+	if err := run([]string{"-no-such-flag"}, &out); err == nil {
+		t.Error("Expected an error for an unknown flag")
+	}
+}
+
+func TestStartTelemetryFailure(t *testing.T) {
+	out := captureLog(t)
+
+	cfg := &opentelemetry.Config{Trace: trace.Config{Exporter: "invalid"}}
+	if startTelemetry(context.Background(), cfg) {
+		t.Fatal("Expected startTelemetry to report a failure for an invalid config")
+	}
+	if !strings.Contains(out.String(), "continuing without telemetry") {
+		t.Errorf("Expected a warning to be logged, got %q", out.String())
+	}
+
+	if got, err := Fibonacci(context.Background(), 10); err != nil || got != 55 {
+		t.Errorf("Expected Fibonacci to still compute 55, got %d (%v)", got, err)
+	}
+}
+
+func TestRunLogsSummaryAtEOF(t *testing.T) {
+	out := captureLog(t)
+	t.Setenv(envStdoutTarget, "none")
+
+	saved := stdin
+	stdin = strings.NewReader("5\nabc\n7\n")
+	defer func() { stdin = saved }()
+
+	done := make(chan error, 1)
+	go func() { done <- run([]string{"-interactive=false"}, io.Discard) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Got an error when should not have: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected run to return once stdin was exhausted")
+	}
+
+	got := out.String()
+	for _, want := range []string{"Fibonacci(5) = 5", "Fibonacci(7) = 13", "shutdown: processed 2 values, 1 errors, uptime"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the log to contain %q, got %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// fibMetrics holds the instruments recorded by Fibonacci
+type fibMetrics struct {
+	computations metric.Int64Counter
+	errors       metric.Int64Counter
+}
+
+// fibStats is created from the global meter provider, so it reports through
+// whichever exporter opentelemetry.Start installs.
+var fibStats = newFibMetrics(otel.Meter("fib"))
+
+func newFibMetrics(meter metric.Meter) *fibMetrics {
+	computations, err := meter.Int64Counter("fib.computations",
+		metric.WithDescription("Number of Fibonacci computations requested."))
+	if err != nil {
+		otel.Handle(err)
+	}
+	errs, err := meter.Int64Counter("fib.errors",
+		metric.WithDescription("Number of Fibonacci computations that failed, by kind."))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &fibMetrics{computations: computations, errors: errs}
+}
+
+// record counts a computation and, when err is non-nil, a failure of its kind.
+// The SDK drops measurements made with a done context, which would hide every
+// cancellation, so the counters are always updated with a fresh one.
+func (m *fibMetrics) record(err error) {
+	ctx := context.Background()
+	m.computations.Add(ctx, 1)
+	if err != nil {
+		m.errors.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", errorKind(err))))
+	}
+}
+
+// errorKind maps err to the low-cardinality label used on fib.errors
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrOverflow):
+		return "overflow"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// useTestMetrics points fibStats at a manual reader for the rest of the test
+func useTestMetrics(t *testing.T) sdkmetric.Reader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	prev := fibStats
+	fibStats = newFibMetrics(provider.Meter("fib"))
+	t.Cleanup(func() { fibStats = prev })
+	return reader
+}
+
+// counterValue returns the value of the named counter for the given kind, or
+// for the unlabelled series when kind is empty
+func counterValue(t *testing.T, reader sdkmetric.Reader, name, kind string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, _ := dp.Attributes.Value("kind"); v.AsString() == kind {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestFibonacciErrorMetric(t *testing.T) {
+	reader := useTestMetrics(t)
+
+	_, _ = Fibonacci(context.Background(), 10)
+	_, _ = Fibonacci(context.Background(), 94)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = Fibonacci(ctx, 10)
+
+	if got := counterValue(t, reader, "fib.computations", ""); got != 3 {
+		t.Errorf("Expected 3 computations but got %d", got)
+	}
+	if got := counterValue(t, reader, "fib.errors", "overflow"); got != 1 {
+		t.Errorf("Expected 1 overflow error but got %d", got)
+	}
+	if got := counterValue(t, reader, "fib.errors", "canceled"); got != 1 {
+		t.Errorf("Expected 1 canceled error but got %d", got)
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrOverflow, "overflow"},
+		{context.Canceled, "canceled"},
+		{context.DeadlineExceeded, "deadline_exceeded"},
+		{ErrReadTimeout, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := errorKind(tt.err); got != tt.want {
+			t.Errorf("errorKind(%v): expected %q but got %q", tt.err, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+
+	"github.com/anzx/pkg/opentelemetry"
+)
+
+// ErrZeroModulus is returned by FibonacciMod for m == 0.
+var ErrZeroModulus = errors.New("modulus must be positive")
+
+// maxPisanoModulus is the largest m whose Pisano period FibonacciMod finds by
+// walking the sequence; the period is at most 6m steps long.
+const maxPisanoModulus = 1 << 20
+
+// ctxCheckEvery is how many loop steps pass between checks of ctx.
+const ctxCheckEvery = 1 << 16
+
+// FibonacciMod returns F(n) mod m. F(n) mod m repeats with the Pisano period
+// of m, which is at most 6m, so for small m and n past that bound, n is first
+// reduced by the period and the rest is a short walk. Larger m, and any n
+// that may be below the period, use fast doubling, which takes O(log n)
+// steps and saves finding the period at all.
+func FibonacciMod(ctx context.Context, n uint, m uint64) (uint64, error) {
+	ctx, spanEnd := opentelemetry.AddSpan(ctx, "Mod")
+	defer spanEnd()
+
+	if m == 0 {
+		return 0, ErrZeroModulus
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if m == 1 {
+		return 0, nil
+	}
+
+	if m > maxPisanoModulus || uint64(n) < 6*m {
+		return fibModDoubling(uint64(n), m), nil
+	}
+
+	period, err := pisanoPeriod(ctx, m)
+	if err != nil {
+		return 0, err
+	}
+	return fibModWalk(ctx, uint64(n)%period, m)
+}
+
+// pisanoPeriod returns the period of F(n) mod m, found as the first return to
+// the pair (0, 1).
+func pisanoPeriod(ctx context.Context, m uint64) (uint64, error) {
+	var a, b uint64 = 0, 1
+	for i := uint64(1); ; i++ {
+		a, b = b, addMod(a, b, m)
+		if a == 0 && b == 1 {
+			return i, nil
+		}
+		if i%ctxCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// fibModWalk computes F(n) mod m one step at a time.
+func fibModWalk(ctx context.Context, n, m uint64) (uint64, error) {
+	var a, b uint64 = 0, 1 % m
+	for i := uint64(0); i < n; i++ {
+		a, b = b, addMod(a, b, m)
+		if i%ctxCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return a, nil
+}
+
+// fibModDoubling computes F(n) mod m with the identities
+// F(2k) = F(k)(2F(k+1) - F(k)) and F(2k+1) = F(k)² + F(k+1)².
+func fibModDoubling(n, m uint64) uint64 {
+	var a, b uint64 = 0, 1 // F(k), F(k+1)
+	for i := bits.Len64(n) - 1; i >= 0; i-- {
+		c := mulMod(a, subMod(addMod(b, b, m), a, m), m)
+		d := addMod(mulMod(a, a, m), mulMod(b, b, m), m)
+		if n>>uint(i)&1 == 0 {
+			a, b = c, d
+		} else {
+			a, b = d, addMod(c, d, m)
+		}
+	}
+	return a
+}
+
+// addMod, subMod and mulMod work on values already reduced mod m without
+// overflowing, for any m.
+func addMod(a, b, m uint64) uint64 {
+	s := a + b
+	if s < a || s >= m {
+		s -= m
+	}
+	return s
+}
+
+func subMod(a, b, m uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return m - (b - a)
+}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, r := bits.Div64(hi, lo, m)
+	return r
+}
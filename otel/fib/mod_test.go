@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// n below 6m may be inside the first period, so these are computed directly;
+// with maxPisanoModulus in the list this only stays fast if the period is
+// never walked
+func TestFibonacciModSmallN(t *testing.T) {
+	ctx := context.Background()
+	for _, m := range []uint64{1, 2, 7, 10, 1000, maxPisanoModulus, maxPisanoModulus + 1, 1 << 40, math.MaxUint64} {
+		for n := uint(0); n <= maxN; n++ {
+			f, _ := Fibonacci(ctx, n)
+			got, err := FibonacciMod(ctx, n, m)
+			if err != nil {
+				t.Fatalf("F(%d) mod %d: got an error when should not have: %v", n, m, err)
+			}
+			if got != f%m {
+				t.Errorf("F(%d) mod %d: expected %d but got %d", n, m, f%m, got)
+			}
+		}
+	}
+}
+
+func TestFibonacciModLargeN(t *testing.T) {
+	ctx := context.Background()
+
+	if p, _ := pisanoPeriod(ctx, 10); p != 60 {
+		t.Errorf("Expected the Pisano period of 10 to be 60 but got %d", p)
+	}
+
+	// 10^12 = 60k + 40, so F(10^12) mod 10 = F(40) mod 10 = 102334155 mod 10
+	if got, err := FibonacciMod(ctx, 1_000_000_000_000, 10); err != nil || got != 5 {
+		t.Errorf("Expected F(10^12) mod 10 = 5 but got %d (%v)", got, err)
+	}
+
+	// the period and doubling paths agree
+	for _, m := range []uint64{3, 1000, 999983} {
+		want := fibModDoubling(123456789, m)
+		if got, _ := FibonacciMod(ctx, 123456789, m); got != want {
+			t.Errorf("F(123456789) mod %d: expected %d but got %d", m, want, got)
+		}
+	}
+}
+
+func TestFibonacciModErrors(t *testing.T) {
+	if _, err := FibonacciMod(context.Background(), 10, 0); !errors.Is(err, ErrZeroModulus) {
+		t.Errorf("Expected ErrZeroModulus but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := FibonacciMod(ctx, 10, 7); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import "os"
+
+// DefaultPrompt is shown before each value when the app runs interactively.
+const DefaultPrompt = "n> "
+
+// isTerminal reports whether f is attached to a terminal rather than a pipe
+// or file.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
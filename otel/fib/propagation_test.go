@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTraceContext(t *testing.T) {
+	captureLog(t)
+
+	sr := tracetest.NewSpanRecorder()
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	usePropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/fib?n=10", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+
+	rr := httptest.NewRecorder()
+	withTraceContext(withRequestID(newServeMux(DefaultMaxBatch))).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, rr.Code)
+	}
+
+	spans := sr.Ended()
+	if len(spans) == 0 {
+		t.Fatal("Expected the request to be traced")
+	}
+	for _, s := range spans {
+		if got := s.SpanContext().TraceID().String(); got != traceID {
+			t.Errorf("Expected span %s in trace %s but got %s", s.Name(), traceID, got)
+		}
+		if s.Name() == "HTTP" && s.Parent().SpanID().String() != parentID {
+			t.Errorf("Expected the HTTP span's parent to be %s but got %s", parentID, s.Parent().SpanID())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+
+	"github.com/anzx/pkg/opentelemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// withRequestID reuses the caller's X-Request-ID (or generates one), stores it
+// in the request context, records it on the request span and in the log, and
+// echoes it back in the response.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		ctx, spanEnd := opentelemetry.AddSpan(r.Context(), "HTTP")
+		defer spanEnd()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+
+		log.Printf("%s %s request_id=%s\n", r.Method, r.URL.Path, id)
+
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, requestIDKey, id)))
+	})
+}
+
+// requestIDFromContext returns the ID stored by withRequestID, if any.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
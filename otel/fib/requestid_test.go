@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestID(t *testing.T) {
+	out := captureLog(t)
+
+	var seen string
+	h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/fib?n=3", nil)
+	req.Header.Set(requestIDHeader, "abc-123")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if seen != "abc-123" || rr.Header().Get(requestIDHeader) != "abc-123" {
+		t.Errorf("Expected the incoming request ID to be preserved, got %q / %q", seen, rr.Header().Get(requestIDHeader))
+	}
+	if !strings.Contains(out.String(), "request_id=abc-123") {
+		t.Errorf("Expected the request ID in the log, got %q", out.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/fib?n=3", nil))
+
+	if seen == "" || seen == "abc-123" || rr.Header().Get(requestIDHeader) != seen {
+		t.Errorf("Expected a generated request ID to be stored and echoed, got %q / %q", seen, rr.Header().Get(requestIDHeader))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+)
+
+// ResultSink receives each value App computes: the result, or the error that
+// stopped it.
+type ResultSink interface {
+	Emit(n uint, result uint64, err error)
+}
+
+// LogSink writes results as log lines, e.g. "Fibonacci(7) = 13".
+type LogSink struct {
+	Logger *log.Logger
+}
+
+func (s LogSink) Emit(n uint, result uint64, err error) {
+	if err != nil {
+		s.Logger.Printf("Fibonacci(%d): %v\n", n, err)
+		return
+	}
+	s.Logger.Printf("Fibonacci(%d) = %s\n", n, FormatResult(result))
+}
+
+// JSONLinesSink writes one JSON object per result, {"n":7,"result":13} or
+// {"n":100,"error":"..."}.
+type JSONLinesSink struct {
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink returns a JSONLinesSink writing to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+// jsonLine is one line of JSONLinesSink output. Result is a pointer so that
+// F(0) is still written.
+type jsonLine struct {
+	N      uint    `json:"n"`
+	Result *uint64 `json:"result,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+func (s *JSONLinesSink) Emit(n uint, result uint64, err error) {
+	line := jsonLine{N: n}
+	if err != nil {
+		line.Error = err.Error()
+	} else {
+		line.Result = &result
+	}
+	_ = s.enc.Encode(line)
+}
+
+// Emitted is one call recorded by SliceSink.
+type Emitted struct {
+	N      uint
+	Result uint64
+	Err    error
+}
+
+// SliceSink records every result it is given, for tests.
+type SliceSink struct {
+	mu      sync.Mutex
+	results []Emitted
+}
+
+func (s *SliceSink) Emit(n uint, result uint64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, Emitted{N: n, Result: result, Err: err})
+}
+
+// Results returns a copy of the results recorded so far.
+func (s *SliceSink) Results() []Emitted {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Emitted(nil), s.results...)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := LogSink{Logger: log.New(&buf, "", 0)}
+
+	sink.Emit(7, 13, nil)
+	sink.Emit(100, 0, ErrOverflow)
+
+	if want := "Fibonacci(7) = 13\nFibonacci(100): " + ErrOverflow.Error() + "\n"; buf.String() != want {
+		t.Errorf("Expected %q but got %q", want, buf.String())
+	}
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLinesSink(&buf)
+
+	sink.Emit(0, 0, nil)
+	sink.Emit(7, 13, nil)
+	sink.Emit(100, 0, ErrOverflow)
+
+	want := `{"n":0,"result":0}` + "\n" + `{"n":7,"result":13}` + "\n" + `{"n":100,"error":"` + ErrOverflow.Error() + `"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q but got %q", want, buf.String())
+	}
+}
+
+func TestSliceSink(t *testing.T) {
+	captureLog(t)
+
+	sink := &SliceSink{}
+	app := NewAppWithConfig(AppConfig{Input: strings.NewReader("5\n50\n7\n"), MaxN: 10, Sink: sink})
+	_ = app.Run(context.Background())
+
+	got := sink.Results()
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 results but got %v", got)
+	}
+	if got[1].N != 50 || got[1].Err == nil {
+		t.Errorf("Expected the skipped input to be emitted with an error, got %+v", got[1])
+	}
+	got[1].Err = nil
+
+	want := []Emitted{{N: 5, Result: 5}, {N: 50}, {N: 7, Result: 13}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v but got %+v", want, got)
+	}
+
+	sink = &SliceSink{}
+	sink.Emit(1, 0, context.Canceled)
+	if r := sink.Results(); len(r) != 1 || !errors.Is(r[0].Err, context.Canceled) {
+		t.Errorf("Expected the error to be recorded as given, got %+v", r)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats summarises what an App has done since it was created.
+type Stats struct {
+	// Processed is the number of values a result or error was written for.
+	Processed int64
+
+	// Errors counts the values that failed and the input lines skipped as
+	// invalid.
+	Errors int64
+
+	Uptime time.Duration
+}
+
+// Stats returns the App's counters. It is safe to call while Run is running.
+func (a *App) Stats() Stats {
+	return Stats{
+		Processed: a.processed.Load(),
+		Errors:    a.failed.Load(),
+		Uptime:    time.Since(a.started),
+	}
+}
+
+// emit counts the result for n and passes it to the sink.
+func (a *App) emit(n uint, f uint64, err error) {
+	a.processed.Add(1)
+	if err != nil {
+		a.failed.Add(1)
+	}
+	a.sink.Emit(n, f, err)
+}
+
+// formatSummary is the line logged when the app shuts down, e.g.
+// "shutdown: processed 12 values, 2 errors, uptime 1m30s".
+func formatSummary(s Stats) string {
+	return fmt.Sprintf("shutdown: processed %d values, %d errors, uptime %s", s.Processed, s.Errors, s.Uptime.Round(time.Second))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Environment variables controlling the stdout trace exporter. Spans are
+// written to stdout by default, where they drown the Fibonacci output.
+//
+//	FIB_OTEL_STDOUT=none           discard spans
+//	FIB_OTEL_STDOUT=stderr         write spans to stderr
+//	FIB_OTEL_STDOUT=/tmp/spans.log append spans to a file
+//	FIB_OTEL_PRETTY=true           indent each span, wherever it is written
+const (
+	envStdoutTarget = "FIB_OTEL_STDOUT"
+	envStdoutPretty = "FIB_OTEL_PRETTY"
+)
+
+// stdoutTraceWriter returns the writer the stdout trace exporter should use for
+// target, and a func to release it. A nil writer means target is empty or
+// "stdout" and the exporter's default should be kept. With pretty set the
+// default is returned as os.Stdout instead, so the exporter is replaced with
+// one that indents.
+func stdoutTraceWriter(target string, pretty bool) (io.Writer, func() error, error) {
+	noop := func() error { return nil }
+
+	switch target {
+	case "", "stdout":
+		if pretty {
+			return os.Stdout, noop, nil
+		}
+		return nil, noop, nil
+	case "none", "off":
+		return io.Discard, noop, nil
+	case "stderr":
+		return os.Stderr, noop, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, noop, err
+	}
+	return f, f.Close, nil
+}
+
+// useStdoutTracer installs a global tracer provider that exports spans to w,
+// replacing the one set up by opentelemetry.Start.
+func useStdoutTracer(w io.Writer, pretty bool) (func(context.Context) error, error) {
+	opts := []stdouttrace.Option{stdouttrace.WithWriter(w)}
+	if pretty {
+		opts = append(opts, stdouttrace.WithPrettyPrint())
+	}
+
+	exporter, err := stdouttrace.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdoutTraceWriter(t *testing.T) {
+	tests := []struct {
+		target string
+		pretty bool
+		want   io.Writer
+	}{
+		{"", false, nil},
+		{"stdout", false, nil},
+		{"", true, os.Stdout},
+		{"stdout", true, os.Stdout},
+		{"none", false, io.Discard},
+		{"off", true, io.Discard},
+		{"stderr", false, os.Stderr},
+		{"stderr", true, os.Stderr},
+	}
+
+	for _, tt := range tests {
+		w, closeFn, err := stdoutTraceWriter(tt.target, tt.pretty)
+		if err != nil {
+			t.Errorf("%q: got an error when should not have: %v", tt.target, err)
+			continue
+		}
+		if w != tt.want {
+			t.Errorf("%q: expected writer %v but got %v", tt.target, tt.want, w)
+		}
+		if err := closeFn(); err != nil {
+			t.Errorf("%q: got an error closing: %v", tt.target, err)
+		}
+	}
+}
+
+func TestStdoutTraceWriterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.log")
+
+	w, closeFn, err := stdoutTraceWriter(path, false)
+	if err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if _, err := io.WriteString(w, "span\n"); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+	if err := closeFn(); err != nil {
+		t.Fatalf("Got an error when should not have: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "span\n" {
+		t.Errorf("Expected the span in %s, got %q (%v)", path, data, err)
+	}
+
+	if _, _, err := stdoutTraceWriter(filepath.Join(t.TempDir(), "missing", "spans.log"), false); err == nil {
+		t.Error("Expected an error for a file in a missing directory")
+	}
+}